@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecryptFileMultipleChunks(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.bin")
+	encrypted := filepath.Join(dir, "plain.bin.enc")
+	decrypted := filepath.Join(dir, "plain.bin.out")
+
+	// bigger than one chunkSize so the loop exercises more than one chunk
+	data := make([]byte, chunkSize+1024)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := os.WriteFile(plain, data, 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	password := []byte("correct horse battery staple")
+	if err := encryptFile(plain, encrypted, password); err != nil {
+		t.Fatalf("encryptFile: %v", err)
+	}
+	if err := decryptFile(encrypted, decrypted, password); err != nil {
+		t.Fatalf("decryptFile: %v", err)
+	}
+
+	got, err := os.ReadFile(decrypted)
+	if err != nil {
+		t.Fatalf("reading decrypted file: %v", err)
+	}
+	if len(got) != len(data) {
+		t.Fatalf("decrypted length = %d, want %d", len(got), len(data))
+	}
+	for i := range data {
+		if got[i] != data[i] {
+			t.Fatalf("decrypted data differs at byte %d", i)
+		}
+	}
+}
+
+func TestDecryptFileRejectsOldFormat(t *testing.T) {
+	dir := t.TempDir()
+	oldStyle := filepath.Join(dir, "legacy.enc")
+	// an old single-blob file: salt || nonce || ciphertext, no stream header
+	if err := os.WriteFile(oldStyle, make([]byte, scrypt_salt_len+scrypt_nonce_len+16), 0644); err != nil {
+		t.Fatalf("writing legacy file: %v", err)
+	}
+
+	err := decryptFile(oldStyle, filepath.Join(dir, "out"), []byte("password"))
+	if err == nil {
+		t.Fatal("expected an error decrypting an old-format file")
+	}
+}
+
+func TestDecryptFileRejectsTamperedChunk(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.bin")
+	encrypted := filepath.Join(dir, "plain.bin.enc")
+
+	if err := os.WriteFile(plain, []byte("some plaintext to protect"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	password := []byte("correct horse battery staple")
+	if err := encryptFile(plain, encrypted, password); err != nil {
+		t.Fatalf("encryptFile: %v", err)
+	}
+
+	data, err := os.ReadFile(encrypted)
+	if err != nil {
+		t.Fatalf("reading encrypted file: %v", err)
+	}
+	data[len(data)-1] ^= 0xFF // flip a bit in the final chunk's ciphertext
+	if err := os.WriteFile(encrypted, data, 0644); err != nil {
+		t.Fatalf("rewriting tampered file: %v", err)
+	}
+
+	err = decryptFile(encrypted, filepath.Join(dir, "out"), password)
+	if err == nil {
+		t.Fatal("expected an error decrypting a tampered file")
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is a log severity, ordered from least to most verbose.
+type Level int
+
+const (
+	LevelError Level = iota
+	LevelWarning
+	LevelInfo
+	LevelDebug
+)
+
+// ParseLevel parses a --log-level flag value.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "error":
+		return LevelError, nil
+	case "warn", "warning":
+		return LevelWarning, nil
+	case "info":
+		return LevelInfo, nil
+	case "debug":
+		return LevelDebug, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q (want error|warn|info|debug)", s)
+	}
+}
+
+// Format selects how a Logger renders its events.
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+)
+
+// ParseFormat parses a --log-format flag value.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format %q (want text|json)", s)
+	}
+}
+
+// field is one structured key/value pair attached to a log event.
+type field struct {
+	key   string
+	value any
+}
+
+// Logger is a small structured, leveled logger with pluggable sinks. Child
+// loggers created via With share the parent's sink and level.
+type Logger struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	level  *Level
+	format Format
+	fields []field
+}
+
+// NewLogger creates a root Logger writing to out at the given level and format.
+func NewLogger(out io.Writer, level Level, format Format) *Logger {
+	lvl := level
+	return &Logger{mu: &sync.Mutex{}, out: out, level: &lvl, format: format}
+}
+
+// SetLevel adjusts the level shared by this Logger and all of its children.
+func (l *Logger) SetLevel(level Level) { *l.level = level }
+
+// With returns a child Logger with an additional structured field attached to
+// every event it logs, e.g. log.With("component", "zip").
+func (l *Logger) With(key string, value any) *Logger {
+	child := *l
+	child.fields = append(append([]field{}, l.fields...), field{key, value})
+	return &child
+}
+
+// Event is a single in-progress log record, built up with chained field
+// setters and finished with Msg/Msgf.
+type Event struct {
+	logger *Logger
+	level  Level
+	fields []field
+}
+
+func (l *Logger) newEvent(level Level) *Event {
+	return &Event{logger: l, level: level, fields: append([]field{}, l.fields...)}
+}
+
+func (l *Logger) Debug() *Event { return l.newEvent(LevelDebug) }
+func (l *Logger) Info() *Event  { return l.newEvent(LevelInfo) }
+func (l *Logger) Warn() *Event  { return l.newEvent(LevelWarning) }
+func (l *Logger) Error() *Event { return l.newEvent(LevelError) }
+
+func (e *Event) Str(key, value string) *Event {
+	e.fields = append(e.fields, field{key, value})
+	return e
+}
+
+func (e *Event) Int(key string, value int) *Event {
+	e.fields = append(e.fields, field{key, value})
+	return e
+}
+
+func (e *Event) Dur(key string, value time.Duration) *Event {
+	e.fields = append(e.fields, field{key, value.String()})
+	return e
+}
+
+func (e *Event) Err(err error) *Event {
+	if err != nil {
+		e.fields = append(e.fields, field{"error", err.Error()})
+	}
+	return e
+}
+
+// Msg finishes the event, dropping it if it's below the logger's level.
+func (e *Event) Msg(msg string) {
+	if e.level > *e.logger.level {
+		return
+	}
+	e.logger.mu.Lock()
+	defer e.logger.mu.Unlock()
+	if e.logger.format == FormatJSON {
+		e.writeJSON(msg)
+	} else {
+		e.writeText(msg)
+	}
+}
+
+// Msgf is Msg with fmt.Sprintf-style formatting.
+func (e *Event) Msgf(format string, args ...any) {
+	e.Msg(fmt.Sprintf(format, args...))
+}
+
+func (e *Event) writeJSON(msg string) {
+	record := make(map[string]any, len(e.fields)+3)
+	record["level"] = e.level.String()
+	record["time"] = time.Now().UTC().Format(time.RFC3339)
+	record["msg"] = msg
+	for _, f := range e.fields {
+		record[f.key] = f.value
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(e.logger.out, string(data))
+}
+
+func (e *Event) writeText(msg string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: %s", time.Now().Format("15:04:05"), strings.ToUpper(e.level.String()), msg)
+	for _, f := range e.fields {
+		fmt.Fprintf(&b, " %s=%v", f.key, f.value)
+	}
+	fmt.Fprintln(e.logger.out, b.String())
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelError:
+		return "error"
+	case LevelWarning:
+		return "warn"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	default:
+		return "unknown"
+	}
+}
+
+// logger is zc's process-wide sink, configured from --log-format/--log-level.
+var logger = NewLogger(os.Stderr, LevelInfo, FormatText)
+
+// parseLogFlags scans args for --log-format=X and --log-level=X, configures
+// the package logger, and returns args with those entries removed so the
+// rest of the CLI's positional parsing doesn't need to know about them.
+func parseLogFlags(args []string) ([]string, error) {
+	var rest []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--log-format="):
+			format, err := ParseFormat(strings.TrimPrefix(arg, "--log-format="))
+			if err != nil {
+				return nil, err
+			}
+			logger.format = format
+		case strings.HasPrefix(arg, "--log-level="):
+			level, err := ParseLevel(strings.TrimPrefix(arg, "--log-level="))
+			if err != nil {
+				return nil, err
+			}
+			logger.SetLevel(level)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestParseLogFlagsStripsKnownFlags(t *testing.T) {
+	defer func() { logger = NewLogger(logger.out, LevelInfo, FormatText) }()
+
+	rest, err := parseLogFlags([]string{"--log-format=json", "--log-level=debug", "myfolder"})
+	if err != nil {
+		t.Fatalf("parseLogFlags: %v", err)
+	}
+	if len(rest) != 1 || rest[0] != "myfolder" {
+		t.Errorf("rest = %v, want [myfolder]", rest)
+	}
+	if logger.format != FormatJSON {
+		t.Errorf("format = %v, want FormatJSON", logger.format)
+	}
+	if *logger.level != LevelDebug {
+		t.Errorf("level = %v, want LevelDebug", *logger.level)
+	}
+}
+
+func TestParseLogFlagsRejectsUnknownValue(t *testing.T) {
+	if _, err := parseLogFlags([]string{"--log-level=verbose"}); err == nil {
+		t.Fatal("expected an error for an unknown log level")
+	}
+}
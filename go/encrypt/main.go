@@ -2,12 +2,13 @@ package main
 
 import (
 	"archive/zip"
+	"bufio"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,6 +28,18 @@ const scrypt_nonce_len = 12
 const scrypt_salt_len = 32
 const scrypt_key_len = 32 // aes-256bit has a 32byte derived key length
 
+// chunked format: magic + version byte + salt + nonce prefix, then a
+// sequence of [uint32 length][12-byte nonce][ciphertext||tag] chunk
+// records. The nonce prefix is a random 4 bytes generated once per file;
+// each chunk's nonce is that prefix concatenated with an 8-byte
+// big-endian chunk counter, so nonces never repeat within a file without
+// needing a fresh random draw (and therefore a fresh scrypt run) per chunk.
+var chunkMagic = [3]byte{'z', 'c', 0x01}
+
+const chunkVersion = 1
+const chunkNoncePrefixLen = 4
+const chunkSize = 1 << 20 // 1 MiB of plaintext per chunk
+
 func getPassword(prompt string) ([]byte, error) {
 	// this function is used to read a password from the terminal
 	// it uses the term package to read the password without echoing it
@@ -47,197 +60,195 @@ func zeroBytes(bytes []byte) {
 	}
 }
 
+// chunkNonce builds the 12-byte GCM nonce for chunk index counter: the
+// file's random 4-byte prefix concatenated with an 8-byte big-endian
+// counter, so nonces never repeat within a file.
+func chunkNonce(noncePrefix [chunkNoncePrefixLen]byte, counter uint64) []byte {
+	nonce := make([]byte, scrypt_nonce_len)
+	copy(nonce, noncePrefix[:])
+	binary.BigEndian.PutUint64(nonce[chunkNoncePrefixLen:], counter)
+	return nonce
+}
+
+// encryptFile streams filename through AES-256-GCM in fixed-size chunks so
+// encrypting a multi-GB input doesn't require holding it all in memory.
 func encryptFile(filename string, encrypted_file_name string, password []byte) error {
-	// Read contents to be encrypted
-	plain_text, err := os.ReadFile(filename)
+	in, err := os.Open(filename)
 	if err != nil {
 		return err
 	}
+	defer in.Close()
 
 	// generate random salt
 	salt := make([]byte, scrypt_salt_len)
 	if _, err := rand.Read(salt); err != nil {
 		return err
 	}
+	defer zeroBytes(salt)
 
-	// derive key from password and salt
-	key, err := scrypt.Key(
-		password,
-		salt,
-		scrypt_N,
-		scrypt_r,
-		scrypt_p,
-		scrypt_key_len)
+	var noncePrefix [chunkNoncePrefixLen]byte
+	if _, err := rand.Read(noncePrefix[:]); err != nil {
+		return err
+	}
 
+	// derive key from password and salt
+	key, err := scrypt.Key(password, salt, scrypt_N, scrypt_r, scrypt_p, scrypt_key_len)
 	if err != nil {
 		return err
 	}
 	defer zeroBytes(key)
-	defer zeroBytes(salt)
 
-	// create cipher block
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return err
 	}
-
 	aesgcm, err := cipher.NewGCM(block)
 	if err != nil {
 		return err
 	}
 
-	// generate random nonce
-	nonce := make([]byte, scrypt_nonce_len)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	out, err := os.Create(encrypted_file_name)
+	if err != nil {
 		return err
 	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
 
-	cipher_text := aesgcm.Seal(nil, nonce, plain_text, nil)
-
-	encrypted_file, err := os.Create(encrypted_file_name)
-	if err != nil {
+	if _, err := w.Write(chunkMagic[:]); err != nil {
 		return err
 	}
-	defer encrypted_file.Close()
-
-	_, err = encrypted_file.Write(salt)
-	if err != nil {
+	if err := w.WriteByte(chunkVersion); err != nil {
 		return err
 	}
-
-	_, err = encrypted_file.Write(nonce)
-	if err != nil {
+	if _, err := w.Write(salt); err != nil {
 		return err
 	}
-	_, err = encrypted_file.Write(cipher_text)
-	if err != nil {
+	if _, err := w.Write(noncePrefix[:]); err != nil {
 		return err
 	}
 
-	return nil
+	buf := make([]byte, chunkSize)
+	r := bufio.NewReader(in)
+	for counter := uint64(0); ; counter++ {
+		n, readErr := io.ReadFull(r, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return readErr
+		}
+
+		nonce := chunkNonce(noncePrefix, counter)
+		cipher_text := aesgcm.Seal(nil, nonce, buf[:n], nil)
+
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(cipher_text)))
+		if _, err := w.Write(length[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(nonce); err != nil {
+			return err
+		}
+		if _, err := w.Write(cipher_text); err != nil {
+			return err
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+
+	return w.Flush()
 }
 
+// decryptFile reverses encryptFile, verifying and decrypting one chunk at a
+// time so decrypting a multi-GB file doesn't require holding it all in
+// memory. It aborts as soon as any chunk fails to authenticate.
 func decryptFile(encrypted_file_name string, decrypted_file_name string, password []byte) error {
-	// Read the encrypted file
-	encrypted_data, err := os.ReadFile(encrypted_file_name)
+	in, err := os.Open(encrypted_file_name)
 	if err != nil {
 		return err
 	}
-	defer zeroBytes(encrypted_data)
-
-	// Extract the salt, nonce, and cipher_text
-	salt := encrypted_data[:scrypt_salt_len]
-	nonce := encrypted_data[scrypt_salt_len : scrypt_salt_len+scrypt_nonce_len]
-	cipher_text := encrypted_data[scrypt_salt_len+scrypt_nonce_len:]
-	key, err := scrypt.Key(
-		password,
-		salt,
-		scrypt_N,
-		scrypt_r,
-		scrypt_p,
-		scrypt_key_len)
-	if err != nil {
-		return err
+	defer in.Close()
+	r := bufio.NewReader(in)
+
+	var magic [3]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return fmt.Errorf("decryption failed: %s is not a zc stream file", encrypted_file_name)
+	}
+	version, err := r.ReadByte()
+	if err != nil || magic != chunkMagic {
+		return fmt.Errorf("decryption failed: %s is not a zc stream file (it may be an older, unsupported .enc format)", encrypted_file_name)
+	}
+	if version != chunkVersion {
+		return fmt.Errorf("decryption failed: %s uses unsupported stream version %d", encrypted_file_name, version)
+	}
+
+	salt := make([]byte, scrypt_salt_len)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return fmt.Errorf("decryption failed: corrupted or tampered header")
 	}
-	defer zeroBytes(key)
 	defer zeroBytes(salt)
 
-	// Create a cipher block
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return err
+	var noncePrefix [chunkNoncePrefixLen]byte
+	if _, err := io.ReadFull(r, noncePrefix[:]); err != nil {
+		return fmt.Errorf("decryption failed: corrupted or tampered header")
 	}
-	aesgcm, err := cipher.NewGCM(block)
+
+	key, err := scrypt.Key(password, salt, scrypt_N, scrypt_r, scrypt_p, scrypt_key_len)
 	if err != nil {
 		return err
 	}
+	defer zeroBytes(key)
 
-	// Decrypt the cipher text
-	plain_text, err := aesgcm.Open(nil, nonce, cipher_text, nil)
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		// if the password is wrong, the error will be "cipher: message authentication failed"
-		if err.Error() == "cipher: message authentication failed" {
-			return fmt.Errorf("decryption failed: invalid password or corrupted file")
-		}
 		return err
 	}
-	defer zeroBytes(plain_text)
-
-	// Write the decrypted data to the output file
-	err = os.WriteFile(decrypted_file_name, plain_text, 0644)
+	aesgcm, err := cipher.NewGCM(block)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-func zipFolder(folder string, zipFileName string) error {
-	// Create output file
-	zipFile, err := os.Create(zipFileName)
+	out, err := os.Create(decrypted_file_name)
 	if err != nil {
 		return err
 	}
-	defer zipFile.Close()
+	defer out.Close()
+	w := bufio.NewWriter(out)
 
-	// Create a zip writer
-	zipWriter := zip.NewWriter(zipFile)
-	defer zipWriter.Close()
-
-	// Walk through the folder and add files to the zip file
-	err = filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	for counter := uint64(0); ; counter++ {
+		var length [4]byte
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("decryption failed: corrupted or tampered archive (truncated chunk %d)", counter)
 		}
 
-		// Create a zip header from the file info
-		header, err := zip.FileInfoHeader(info)
-		if err != nil {
-			return err
+		nonce := make([]byte, scrypt_nonce_len)
+		if _, err := io.ReadFull(r, nonce); err != nil {
+			return fmt.Errorf("decryption failed: corrupted or tampered archive (truncated chunk %d)", counter)
 		}
-
-		// Set the header name to the relative path
-		header.Name, err = filepath.Rel(filepath.Dir(folder), path)
-		if err != nil {
-			return err
+		if string(nonce) != string(chunkNonce(noncePrefix, counter)) {
+			return fmt.Errorf("decryption failed: corrupted or tampered archive (unexpected nonce at chunk %d)", counter)
 		}
 
-		// If the file is a directory, add a trailing slash to the header name
-		if info.IsDir() {
-			header.Name += "/"
-		} else {
-			// Set the compression method for files
-			header.Method = zip.Deflate
+		cipher_text := make([]byte, binary.BigEndian.Uint32(length[:]))
+		if _, err := io.ReadFull(r, cipher_text); err != nil {
+			return fmt.Errorf("decryption failed: corrupted or tampered archive (truncated chunk %d)", counter)
 		}
 
-		// Create a writer for the file in the zip archive
-		writer, err := zipWriter.CreateHeader(header)
+		plain_text, err := aesgcm.Open(nil, nonce, cipher_text, nil)
 		if err != nil {
-			return err
+			return fmt.Errorf("decryption failed: corrupted or tampered archive (invalid password or tampered chunk %d)", counter)
 		}
 
-		// If the file is not a directory, copy its contents to the zip writer
-		if !info.IsDir() {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-
-			_, err = io.Copy(writer, file)
-			if err != nil {
-				return err
-			}
+		if _, err := w.Write(plain_text); err != nil {
+			zeroBytes(plain_text)
+			return err
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return err
+		zeroBytes(plain_text)
 	}
 
-	return nil
+	return w.Flush()
 }
 
 func unzipFolder(zipFileName, folder string) error {
@@ -304,12 +315,18 @@ func unzipFolder(zipFileName, folder string) error {
 func printHelp() {
 	fmt.Printf("Usage: %s [options] <folder name or file that ends in %s>\n\n", tool_name, tool_ext)
 	fmt.Printf("Options:\n")
-	fmt.Printf("  -h, --help     Show this help message and exit\n")
-	fmt.Printf("  -v, --version  Show version information and exit\n\n")
+	fmt.Printf("  -h, --help             Show this help message and exit\n")
+	fmt.Printf("  -v, --version          Show version information and exit\n")
+	fmt.Printf("  --log-format=FORMAT    Log output format: text|json (default text)\n")
+	fmt.Printf("  --log-level=LEVEL      Log level: error|warn|info|debug (default info)\n")
+	fmt.Printf("  --zip-crypto           Encrypt as a standard ZIP using traditional PKWARE ZipCrypto\n")
+	fmt.Printf("  --zip-aes              Encrypt as a standard ZIP using WinZip AES-256\n\n")
 	fmt.Printf("Description:\n")
 	fmt.Printf("  Provides compression of a folder, encryption and decryption.\n")
 	fmt.Printf("  - To encrypt a folder: %s <folder_name>\n", tool_name)
 	fmt.Printf("  - To decrypt a file: %s <filename%s>\n", tool_name, tool_ext)
+	fmt.Printf("  --zip-crypto/--zip-aes produce a single standards-compliant .zip\n")
+	fmt.Printf("  instead, openable by 7-Zip, Archive Utility, or unzip without %s.\n", tool_name)
 }
 
 func printVersion() {
@@ -317,6 +334,15 @@ func printVersion() {
 }
 
 func cli(args []string) error {
+	args, err := parseLogFlags(args)
+	if err != nil {
+		return err
+	}
+	args, zipMode, err := parseZipFlags(args)
+	if err != nil {
+		return err
+	}
+
 	// check args
 	if len(args) == 0 {
 		printHelp()
@@ -339,14 +365,17 @@ func cli(args []string) error {
 		return fmt.Errorf("usage: %s <folder name or file that ends in .enc>", tool_name)
 	}
 
-	// argument must be a directory or a file that ends with .enc
+	// argument must be a directory or a file that ends with .enc or .zip
 	filename := args[0]
 	fileInfo, err := os.Stat(filename)
 	if err != nil {
 		return fmt.Errorf("file does not exist: %s", filename)
 	}
-	if !fileInfo.IsDir() && !strings.HasSuffix(filename, ".enc") {
-		return fmt.Errorf("file is not a directory, and doesn't have a '%s' extension: %s", filename, tool_ext)
+	if !fileInfo.IsDir() && !strings.HasSuffix(filename, tool_ext) && !strings.HasSuffix(filename, ".zip") {
+		return fmt.Errorf("file is not a directory, and doesn't have a '%s' or '.zip' extension: %s", tool_ext, filename)
+	}
+	if !fileInfo.IsDir() && zipMode != zipCryptoNone {
+		return fmt.Errorf("--zip-crypto/--zip-aes only apply when encrypting a folder")
 	}
 
 	// make temp dir in the current directory to prevent leaks into the real temp dir
@@ -369,9 +398,10 @@ func cli(args []string) error {
 	defer zeroBytes(password)
 
 	// do the work
-	if strings.HasSuffix(filename, ".enc") {
-		log.Println("Decrypting file...")
-		output := strings.TrimSuffix(filename, ".enc")
+	switch {
+	case strings.HasSuffix(filename, tool_ext):
+		logger.Info().Msg("Decrypting file...")
+		output := strings.TrimSuffix(filename, tool_ext)
 
 		// Check if the output directory already exists
 		if _, err := os.Stat(output); err == nil {
@@ -384,23 +414,43 @@ func cli(args []string) error {
 		if err := decryptFile(filename, zipFile, password); err != nil {
 			return fmt.Errorf("error decrypting file: %v", err)
 		}
-		log.Println("Unzipping file...")
+		logger.Info().Msg("Unzipping file...")
 		if err := unzipFolder(zipFile, wd); err != nil {
 			return fmt.Errorf("error unzipping file: %v", err)
 		}
-	} else {
-		log.Println("Zipping folder...")
-		output := filename + ".enc"
+
+	case strings.HasSuffix(filename, ".zip"):
+		logger.Info().Msg("Decrypting password-protected zip...")
+		output := strings.TrimSuffix(filename, ".zip")
+		if _, err := os.Stat(output); err == nil {
+			return fmt.Errorf("output directory already exists: %s", output)
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("error checking output directory: %v", err)
+		}
+		if err := decryptZipFolder(filename, wd, password); err != nil {
+			return fmt.Errorf("error decrypting zip file: %v", err)
+		}
+
+	case zipMode != zipCryptoNone:
+		logger.Info().Msg("Zipping and encrypting folder as a password-protected zip...")
+		output := filename + ".zip"
+		if err := encryptZipFolder(filename, output, password, zipMode); err != nil {
+			return fmt.Errorf("error encrypting zip folder: %v", err)
+		}
+
+	default:
+		logger.Info().Msg("Zipping folder...")
+		output := filename + tool_ext
 		zipFile := filepath.Join(temp, filepath.Base(output))
 		if err := zipFolder(filename, zipFile); err != nil {
 			return fmt.Errorf("error zipping folder: %v", err)
 		}
-		log.Println("Encrypting file...")
+		logger.Info().Msg("Encrypting file...")
 		if err := encryptFile(zipFile, output, password); err != nil {
 			return fmt.Errorf("error encrypting file: %v", err)
 		}
 	}
-	log.Println("Done!")
+	logger.Info().Msg("Done!")
 	return nil
 }
 
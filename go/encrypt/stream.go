@@ -0,0 +1,360 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Streaming container format: a fixed header followed by a sequence of
+// length-prefixed, independently-sealed blocks. Each block is authenticated
+// on its own, so a corrupted or truncated archive fails fast at the
+// offending block instead of only being caught at the very end.
+const (
+	streamMagic                = "ZCST"
+	streamVersion        uint8 = 1
+	defaultBlockSize           = 128 * 1024 // 128 KiB, following syncthing's block model
+	streamNoncePrefixLen       = 4          // random per-file prefix; the remaining 8 bytes of the nonce are the block index
+)
+
+// streamHeader precedes the block stream and lets decryption verify the
+// magic and version before touching any data. BlockSize is recorded but not
+// checked on decryption, which reads each block's length from its prefix.
+type streamHeader struct {
+	Magic       [4]byte
+	Version     uint8
+	BlockSize   uint32
+	Salt        [scrypt_salt_len]byte
+	NoncePrefix [streamNoncePrefixLen]byte
+}
+
+func writeStreamHeader(w io.Writer, h streamHeader) error {
+	if _, err := w.Write(h.Magic[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, h.Version); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, h.BlockSize); err != nil {
+		return err
+	}
+	if _, err := w.Write(h.Salt[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(h.NoncePrefix[:])
+	return err
+}
+
+func readStreamHeader(r io.Reader) (streamHeader, error) {
+	var h streamHeader
+	if _, err := io.ReadFull(r, h.Magic[:]); err != nil {
+		return h, err
+	}
+	if string(h.Magic[:]) != streamMagic {
+		return h, fmt.Errorf("not a streaming %s archive", tool_name)
+	}
+	if err := binary.Read(r, binary.BigEndian, &h.Version); err != nil {
+		return h, err
+	}
+	if h.Version != streamVersion {
+		return h, fmt.Errorf("unsupported stream version %d", h.Version)
+	}
+	if err := binary.Read(r, binary.BigEndian, &h.BlockSize); err != nil {
+		return h, err
+	}
+	if _, err := io.ReadFull(r, h.Salt[:]); err != nil {
+		return h, err
+	}
+	if _, err := io.ReadFull(r, h.NoncePrefix[:]); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+func blockNonce(prefix [streamNoncePrefixLen]byte, index uint64) []byte {
+	nonce := make([]byte, scrypt_nonce_len)
+	copy(nonce, prefix[:])
+	binary.BigEndian.PutUint64(nonce[streamNoncePrefixLen:], index)
+	return nonce
+}
+
+func newAEAD(password, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(password, salt, scrypt_N, scrypt_r, scrypt_p, scrypt_key_len)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealedBlock is a completed ciphertext block, tagged with its position so an
+// ordered writer can flush blocks in the order they were read regardless of
+// which worker finished them first.
+type sealedBlock struct {
+	index int
+	data  []byte
+	err   error
+}
+
+// StreamZipEncrypt zips srcDir and encrypts it block by block, streaming both
+// stages instead of staging the whole archive and ciphertext in memory.
+func StreamZipEncrypt(srcDir, dstPath string, password []byte, blockSize int) error {
+	if blockSize <= 0 {
+		blockSize = defaultBlockSize
+	}
+
+	salt := make([]byte, scrypt_salt_len)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	defer zeroBytes(salt)
+
+	var noncePrefix [streamNoncePrefixLen]byte
+	if _, err := rand.Read(noncePrefix[:]); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(password, salt)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var header streamHeader
+	copy(header.Magic[:], streamMagic)
+	header.Version = streamVersion
+	header.BlockSize = uint32(blockSize)
+	copy(header.Salt[:], salt)
+	header.NoncePrefix = noncePrefix
+	if err := writeStreamHeader(out, header); err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	zipErrCh := make(chan error, 1)
+	go func() {
+		zipErrCh <- zipFolderTo(srcDir, pw)
+		pw.Close()
+	}()
+
+	workers := runtime.NumCPU()
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan struct {
+		index int
+		plain []byte
+	}, workers)
+	results := make(chan sealedBlock, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				ciphertext := aead.Seal(nil, blockNonce(noncePrefix, uint64(job.index)), job.plain, nil)
+				results <- sealedBlock{index: job.index, data: ciphertext}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	writeErrCh := make(chan error, 1)
+	go func() {
+		writeErrCh <- writeBlocksInOrder(out, results)
+	}()
+
+	readErr := func() error {
+		index := 0
+		buf := make([]byte, blockSize)
+		for {
+			n, err := io.ReadFull(pr, buf)
+			if n > 0 {
+				plain := make([]byte, n)
+				copy(plain, buf[:n])
+				jobs <- struct {
+					index int
+					plain []byte
+				}{index: index, plain: plain}
+				index++
+			}
+			switch err {
+			case nil:
+				continue
+			case io.EOF, io.ErrUnexpectedEOF:
+				return nil
+			default:
+				return err
+			}
+		}
+	}()
+	close(jobs)
+
+	if err := <-writeErrCh; err != nil {
+		return err
+	}
+	if readErr != nil {
+		return readErr
+	}
+	return <-zipErrCh
+}
+
+// writeBlocksInOrder buffers out-of-order results until the next expected
+// index is available, then flushes as a length-prefixed block.
+func writeBlocksInOrder(w io.Writer, results <-chan sealedBlock) error {
+	pending := make(map[int][]byte)
+	next := 0
+	for block := range results {
+		if block.err != nil {
+			return block.err
+		}
+		pending[block.index] = block.data
+		for {
+			data, ok := pending[next]
+			if !ok {
+				break
+			}
+			if err := binary.Write(w, binary.BigEndian, uint32(len(data))); err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			delete(pending, next)
+			next++
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("stream encrypt: %d blocks never reached index %d", len(pending), next)
+	}
+	return nil
+}
+
+// StreamDecryptUnzip verifies and decrypts a StreamZipEncrypt archive block
+// by block, writing the recovered zip to a temp file before extracting it.
+func StreamDecryptUnzip(srcPath, dstDir string, password []byte) error {
+	in, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	header, err := readStreamHeader(in)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(password, header.Salt[:])
+	if err != nil {
+		return err
+	}
+
+	tmpZip, err := os.CreateTemp(filepath.Dir(dstDir), "zcstream-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpZip.Name())
+	defer tmpZip.Close()
+
+	for index := uint64(0); ; index++ {
+		var length uint32
+		if err := binary.Read(in, binary.BigEndian, &length); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		ciphertext := make([]byte, length)
+		if _, err := io.ReadFull(in, ciphertext); err != nil {
+			return fmt.Errorf("corrupted or tampered archive: truncated block %d: %w", index, err)
+		}
+
+		plain, err := aead.Open(nil, blockNonce(header.NoncePrefix, index), ciphertext, nil)
+		if err != nil {
+			return fmt.Errorf("corrupted or tampered archive: block %d failed authentication", index)
+		}
+
+		if _, err := tmpZip.Write(plain); err != nil {
+			return err
+		}
+	}
+
+	if err := tmpZip.Close(); err != nil {
+		return err
+	}
+
+	return unzipFolder(tmpZip.Name(), dstDir)
+}
+
+// zipFolderTo zips folder into w instead of a named file, so encryption can
+// consume the archive as a stream rather than staging it on disk.
+func zipFolderTo(folder string, w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+	defer zipWriter.Close()
+
+	return filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+
+		header.Name, err = filepath.Rel(filepath.Dir(folder), path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(writer, file)
+		return err
+	})
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamZipEncryptRoundTrip(t *testing.T) {
+	password := []byte("streamsecret")
+	testFolder := "stream_test_folder"
+	encryptedFile := testFolder + ".zcstream"
+	decryptedFolder := testFolder + "_decrypted"
+
+	cleanupFiles := []string{testFolder, encryptedFile, decryptedFolder}
+	defer cleanup(cleanupFiles)
+	cleanup(cleanupFiles)
+
+	// small block size plus one file several blocks long forces that file to
+	// span multiple blocks and straddle block boundaries.
+	const blockSize = 1024
+	generateTestFiles(testFolder, 3)
+	bigFile := filepath.Join(testFolder, "big.txt")
+	bigContent := make([]byte, blockSize*3+17)
+	for i := range bigContent {
+		bigContent[i] = byte(i % 251)
+	}
+	if err := os.WriteFile(bigFile, bigContent, 0644); err != nil {
+		t.Fatalf("writing big file: %v", err)
+	}
+
+	if err := StreamZipEncrypt(testFolder, encryptedFile, password, blockSize); err != nil {
+		t.Fatalf("StreamZipEncrypt: %v", err)
+	}
+
+	if err := StreamDecryptUnzip(encryptedFile, decryptedFolder, password); err != nil {
+		t.Fatalf("StreamDecryptUnzip: %v", err)
+	}
+
+	if err := verifyFolderContents(testFolder, filepath.Join(decryptedFolder, testFolder)); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestStreamDecryptTruncated(t *testing.T) {
+	password := []byte("streamsecret")
+	testFolder := "stream_trunc_folder"
+	encryptedFile := testFolder + ".zcstream"
+	decryptedFolder := testFolder + "_decrypted"
+
+	cleanupFiles := []string{testFolder, encryptedFile, decryptedFolder}
+	defer cleanup(cleanupFiles)
+	cleanup(cleanupFiles)
+
+	generateTestFiles(testFolder, 5)
+	if err := StreamZipEncrypt(testFolder, encryptedFile, password, 256); err != nil {
+		t.Fatalf("StreamZipEncrypt: %v", err)
+	}
+
+	data, err := os.ReadFile(encryptedFile)
+	if err != nil {
+		t.Fatalf("reading encrypted file: %v", err)
+	}
+	if len(data) < 10 {
+		t.Fatalf("encrypted file too small to truncate meaningfully")
+	}
+	if err := os.WriteFile(encryptedFile, data[:len(data)-10], 0644); err != nil {
+		t.Fatalf("truncating encrypted file: %v", err)
+	}
+
+	if err := StreamDecryptUnzip(encryptedFile, decryptedFolder, password); err == nil {
+		t.Fatal("expected truncated archive to fail decryption")
+	}
+}
+
+func TestStreamDecryptBitFlip(t *testing.T) {
+	password := []byte("streamsecret")
+	testFolder := "stream_flip_folder"
+	encryptedFile := testFolder + ".zcstream"
+	decryptedFolder := testFolder + "_decrypted"
+
+	cleanupFiles := []string{testFolder, encryptedFile, decryptedFolder}
+	defer cleanup(cleanupFiles)
+	cleanup(cleanupFiles)
+
+	generateTestFiles(testFolder, 5)
+	if err := StreamZipEncrypt(testFolder, encryptedFile, password, 256); err != nil {
+		t.Fatalf("StreamZipEncrypt: %v", err)
+	}
+
+	data, err := os.ReadFile(encryptedFile)
+	if err != nil {
+		t.Fatalf("reading encrypted file: %v", err)
+	}
+	// flip a bit in the file's last byte, inside the final block's authentication tag
+	offset := len(data) - 1
+	data[offset] ^= 0x01
+	if err := os.WriteFile(encryptedFile, data, 0644); err != nil {
+		t.Fatalf("writing corrupted file: %v", err)
+	}
+
+	if err := StreamDecryptUnzip(encryptedFile, decryptedFolder, password); err == nil {
+		t.Fatal("expected bit-flipped archive to fail authentication")
+	}
+}
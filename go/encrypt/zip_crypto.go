@@ -0,0 +1,411 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// zipCryptoMode selects which password-protected ZIP scheme encryptZipFolder
+// writes. Both are interoperable with off-the-shelf archive tools (7-Zip,
+// macOS Archive Utility, unzip -P), unlike the custom .enc container the
+// rest of this tool produces by default.
+type zipCryptoMode int
+
+const (
+	zipCryptoNone zipCryptoMode = iota
+	// zipCryptoTraditional is the original PKWARE "ZipCrypto" stream cipher.
+	// It's supported everywhere but cryptographically weak; offered only for
+	// compatibility with very old unzip tools.
+	zipCryptoTraditional
+	// zipCryptoAES is WinZip's AE-1 scheme: AES-256-CTR with an HMAC-SHA1
+	// authentication code, the de facto standard for "real" encrypted zips.
+	zipCryptoAES
+)
+
+const (
+	aesExtraFieldID   = 0x9901
+	aesVendorVersion1 = 1 // AE-1: CRC-32 of the plaintext is still stored
+	aesStrength256    = 3
+	aesSaltLen        = 16 // salt length for AES-256
+	aesKeyLen         = 32 // AES-256 key length
+	aesPwVerifyLen    = 2
+	aesAuthCodeLen    = 10 // WinZip truncates the HMAC-SHA1 to 10 bytes
+	pbkdf2Iterations  = 1000
+
+	zipCryptoHeaderLen = 12
+)
+
+// encryptZipFolder walks folder and writes a single password-protected,
+// standards-compliant ZIP to zipFileName: each entry is compressed then
+// encrypted individually, per mode.
+func encryptZipFolder(folder, zipFileName string, password []byte, mode zipCryptoMode) error {
+	if mode == zipCryptoNone {
+		return fmt.Errorf("encryptZipFolder: a zip crypto mode is required")
+	}
+
+	out, err := os.Create(zipFileName)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	zipWriter := zip.NewWriter(out)
+	defer zipWriter.Close()
+
+	return filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relName, err := filepath.Rel(filepath.Dir(folder), path)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = relName
+
+		if info.IsDir() {
+			header.Name += "/"
+			_, err := zipWriter.CreateHeader(header)
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		compressed, err := deflateBlock(content, true)
+		if err != nil {
+			return err
+		}
+		header.Method = zip.Deflate
+		header.UncompressedSize64 = uint64(len(content))
+		header.CRC32 = crc32.ChecksumIEEE(content)
+
+		var sealed []byte
+		switch mode {
+		case zipCryptoAES:
+			sealed, err = sealAESEntry(compressed, password)
+			if err != nil {
+				return err
+			}
+			header.Method = 99 // WinZip "AE-x" method number
+			header.Extra = append(header.Extra, aesExtraField(zip.Deflate)...)
+		case zipCryptoTraditional:
+			sealed, err = sealTraditionalEntry(compressed, password, header.CRC32)
+			if err != nil {
+				return err
+			}
+			header.Flags |= 0x1 // bit 0: file is encrypted
+		}
+		header.CompressedSize64 = uint64(len(sealed))
+
+		w, err := zipWriter.CreateRaw(header)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(sealed)
+		return err
+	})
+}
+
+// decryptZipFolder reverses encryptZipFolder: it reads each entry, decrypts
+// it per the scheme its header declares, inflates it, and writes it under
+// folder using the entry's stored relative path (mirroring unzipFolder).
+func decryptZipFolder(zipFileName, folder string, password []byte) error {
+	zipFile, err := os.Open(zipFileName)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	stat, err := zipFile.Stat()
+	if err != nil {
+		return err
+	}
+	zipReader, err := zip.NewReader(zipFile, stat.Size())
+	if err != nil {
+		return err
+	}
+
+	for _, zf := range zipReader.File {
+		filePath := filepath.Join(folder, zf.Name)
+		if !strings.HasPrefix(filepath.Clean(filePath), filepath.Clean(folder)+string(os.PathSeparator)) {
+			return fmt.Errorf("%s is not located in %s", filePath, folder)
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(filePath, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := zf.OpenRaw()
+		if err != nil {
+			return err
+		}
+		sealed, err := io.ReadAll(rc)
+		if err != nil {
+			return err
+		}
+
+		var compressed []byte
+		switch {
+		case zf.Method == 99:
+			compressed, err = openAESEntry(sealed, password)
+		case zf.Flags&0x1 != 0:
+			compressed, err = openTraditionalEntry(sealed, password, byte(zf.CRC32>>24))
+		default:
+			return fmt.Errorf("%s is not a password-protected entry", zf.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("decrypting %s: %w", zf.Name, err)
+		}
+
+		plain, err := inflateAll(compressed)
+		if err != nil {
+			return fmt.Errorf("inflating %s: %w", zf.Name, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filePath, plain, zf.Mode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseZipFlags scans args for --zip-crypto/--zip-aes, returning args with
+// those entries removed and the requested mode (zipCryptoNone if neither was
+// given).
+func parseZipFlags(args []string) ([]string, zipCryptoMode, error) {
+	mode := zipCryptoNone
+	var rest []string
+	for _, arg := range args {
+		switch arg {
+		case "--zip-crypto", "--zip-aes":
+			if mode != zipCryptoNone {
+				return nil, zipCryptoNone, fmt.Errorf("only one of --zip-crypto or --zip-aes may be given")
+			}
+			if arg == "--zip-crypto" {
+				mode = zipCryptoTraditional
+			} else {
+				mode = zipCryptoAES
+			}
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, mode, nil
+}
+
+// aesExtraField builds the WinZip 0x9901 "AE-x" extra field declaring AE-1,
+// AES-256, and the real compression method used underneath the encryption.
+func aesExtraField(actualMethod uint16) []byte {
+	buf := make([]byte, 11)
+	binary.LittleEndian.PutUint16(buf[0:2], aesExtraFieldID)
+	binary.LittleEndian.PutUint16(buf[2:4], 7) // data size
+	binary.LittleEndian.PutUint16(buf[4:6], aesVendorVersion1)
+	copy(buf[6:8], "AE")
+	buf[8] = aesStrength256
+	binary.LittleEndian.PutUint16(buf[9:11], actualMethod)
+	return buf
+}
+
+// sealAESEntry derives per-entry keys from password via PBKDF2-HMAC-SHA1 and
+// returns salt || password-verification-value || ciphertext || HMAC, the
+// on-disk layout WinZip's AE-x scheme expects.
+func sealAESEntry(plain, password []byte) ([]byte, error) {
+	salt := make([]byte, aesSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	derived := pbkdf2.Key(password, salt, pbkdf2Iterations, 2*aesKeyLen+aesPwVerifyLen, sha1.New)
+	encKey := derived[:aesKeyLen]
+	hmacKey := derived[aesKeyLen : 2*aesKeyLen]
+	pwVerify := derived[2*aesKeyLen:]
+
+	ciphertext, err := winzipCTRCrypt(encKey, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha1.New, hmacKey)
+	mac.Write(ciphertext)
+	authCode := mac.Sum(nil)[:aesAuthCodeLen]
+
+	out := make([]byte, 0, len(salt)+len(pwVerify)+len(ciphertext)+len(authCode))
+	out = append(out, salt...)
+	out = append(out, pwVerify...)
+	out = append(out, ciphertext...)
+	out = append(out, authCode...)
+	return out, nil
+}
+
+// openAESEntry reverses sealAESEntry, rejecting a wrong password (bad
+// verification value) or a tampered/corrupted entry (bad HMAC) before ever
+// touching the decompressor.
+func openAESEntry(sealed, password []byte) ([]byte, error) {
+	if len(sealed) < aesSaltLen+aesPwVerifyLen+aesAuthCodeLen {
+		return nil, fmt.Errorf("entry too short to be a WinZip AES entry")
+	}
+	salt := sealed[:aesSaltLen]
+	pwVerify := sealed[aesSaltLen : aesSaltLen+aesPwVerifyLen]
+	ciphertext := sealed[aesSaltLen+aesPwVerifyLen : len(sealed)-aesAuthCodeLen]
+	wantAuthCode := sealed[len(sealed)-aesAuthCodeLen:]
+
+	derived := pbkdf2.Key(password, salt, pbkdf2Iterations, 2*aesKeyLen+aesPwVerifyLen, sha1.New)
+	encKey := derived[:aesKeyLen]
+	hmacKey := derived[aesKeyLen : 2*aesKeyLen]
+	wantPwVerify := derived[2*aesKeyLen:]
+
+	if subtle.ConstantTimeCompare(pwVerify, wantPwVerify) != 1 {
+		return nil, fmt.Errorf("invalid password")
+	}
+
+	mac := hmac.New(sha1.New, hmacKey)
+	mac.Write(ciphertext)
+	gotAuthCode := mac.Sum(nil)[:aesAuthCodeLen]
+	if subtle.ConstantTimeCompare(gotAuthCode, wantAuthCode) != 1 {
+		return nil, fmt.Errorf("corrupted or tampered entry (HMAC mismatch)")
+	}
+
+	return winzipCTRCrypt(encKey, ciphertext)
+}
+
+// winzipCTRCrypt implements WinZip's variant of AES-CTR: a 128-bit counter
+// starting at 1, encoded little-endian (the stdlib's cipher.NewCTR treats
+// its nonce as a big-endian counter, so it can't be reused here). XOR is its
+// own inverse, so the same function encrypts and decrypts.
+func winzipCTRCrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(data))
+	var counterBlock, keystream [aes.BlockSize]byte
+	counter := uint64(1)
+	for offset := 0; offset < len(data); offset += aes.BlockSize {
+		binary.LittleEndian.PutUint64(counterBlock[:8], counter)
+		block.Encrypt(keystream[:], counterBlock[:])
+
+		end := offset + aes.BlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		for i := offset; i < end; i++ {
+			out[i] = data[i] ^ keystream[i-offset]
+		}
+		counter++
+	}
+	return out, nil
+}
+
+// traditionalKeys holds the three 32-bit running keys PKWARE's "ZipCrypto"
+// stream cipher updates on every plaintext byte.
+type traditionalKeys struct {
+	key0, key1, key2 uint32
+}
+
+func newTraditionalKeys(password []byte) *traditionalKeys {
+	k := &traditionalKeys{key0: 0x12345678, key1: 0x23456789, key2: 0x34567890}
+	for _, b := range password {
+		k.update(b)
+	}
+	return k
+}
+
+func (k *traditionalKeys) update(b byte) {
+	k.key0 = crc32Update(k.key0, b)
+	k.key1 = (k.key1+(k.key0&0xff))*134775813 + 1
+	k.key2 = crc32Update(k.key2, byte(k.key1>>24))
+}
+
+// keystreamByte derives the next byte of keystream from key2, per the
+// PKWARE APPNOTE.TXT algorithm.
+func (k *traditionalKeys) keystreamByte() byte {
+	temp := uint16(k.key2 | 2)
+	return byte((temp * (temp ^ 1)) >> 8)
+}
+
+func crc32Update(crc uint32, b byte) uint32 {
+	return (crc >> 8) ^ crc32.IEEETable[byte(crc)^b]
+}
+
+// sealTraditionalEntry encrypts plain with the classic PKWARE stream cipher:
+// a 12-byte random header (whose last byte verifies the password against
+// crcHigh) followed by the ciphertext.
+func sealTraditionalEntry(plain, password []byte, crc uint32) ([]byte, error) {
+	header := make([]byte, zipCryptoHeaderLen)
+	if _, err := rand.Read(header); err != nil {
+		return nil, err
+	}
+	header[zipCryptoHeaderLen-1] = byte(crc >> 24)
+
+	keys := newTraditionalKeys(password)
+	out := make([]byte, 0, len(header)+len(plain))
+	for _, b := range header {
+		out = append(out, traditionalEncryptByte(keys, b))
+	}
+	for _, b := range plain {
+		out = append(out, traditionalEncryptByte(keys, b))
+	}
+	return out, nil
+}
+
+func traditionalEncryptByte(keys *traditionalKeys, plainByte byte) byte {
+	cipherByte := plainByte ^ keys.keystreamByte()
+	keys.update(plainByte)
+	return cipherByte
+}
+
+func traditionalDecryptByte(keys *traditionalKeys, cipherByte byte) byte {
+	plainByte := cipherByte ^ keys.keystreamByte()
+	keys.update(plainByte)
+	return plainByte
+}
+
+// openTraditionalEntry reverses sealTraditionalEntry and checks the header's
+// password-verification byte against the entry's stored CRC before trusting
+// the rest of the stream.
+func openTraditionalEntry(sealed, password []byte, crcHigh byte) ([]byte, error) {
+	if len(sealed) < zipCryptoHeaderLen {
+		return nil, fmt.Errorf("entry too short to be a ZipCrypto entry")
+	}
+
+	keys := newTraditionalKeys(password)
+	header := make([]byte, zipCryptoHeaderLen)
+	for i, b := range sealed[:zipCryptoHeaderLen] {
+		header[i] = traditionalDecryptByte(keys, b)
+	}
+	if header[zipCryptoHeaderLen-1] != crcHigh {
+		return nil, fmt.Errorf("invalid password")
+	}
+
+	plain := make([]byte, len(sealed)-zipCryptoHeaderLen)
+	for i, b := range sealed[zipCryptoHeaderLen:] {
+		plain[i] = traditionalDecryptByte(keys, b)
+	}
+	return plain, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setUpZipCryptoFolder(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	folder := filepath.Join(dir, "payload")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatalf("creating folder: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(folder, "a.txt"), []byte("hello from a"), 0644); err != nil {
+		t.Fatalf("writing a.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(folder, "b.txt"), []byte("hello from b, a bit longer this time"), 0644); err != nil {
+		t.Fatalf("writing b.txt: %v", err)
+	}
+	return folder
+}
+
+func TestEncryptDecryptZipFolderAES(t *testing.T) {
+	folder := setUpZipCryptoFolder(t)
+	zipPath := folder + ".zip"
+	password := []byte("correct horse battery staple")
+
+	if err := encryptZipFolder(folder, zipPath, password, zipCryptoAES); err != nil {
+		t.Fatalf("encryptZipFolder: %v", err)
+	}
+
+	out := filepath.Dir(folder)
+	if err := decryptZipFolder(zipPath, out, password); err != nil {
+		t.Fatalf("decryptZipFolder: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(folder, "a.txt"))
+	if err != nil {
+		t.Fatalf("reading decrypted a.txt: %v", err)
+	}
+	if string(got) != "hello from a" {
+		t.Errorf("a.txt = %q, want %q", got, "hello from a")
+	}
+}
+
+func TestEncryptDecryptZipFolderTraditional(t *testing.T) {
+	folder := setUpZipCryptoFolder(t)
+	zipPath := folder + ".zip"
+	password := []byte("another password")
+
+	if err := encryptZipFolder(folder, zipPath, password, zipCryptoTraditional); err != nil {
+		t.Fatalf("encryptZipFolder: %v", err)
+	}
+
+	out := filepath.Dir(folder)
+	if err := decryptZipFolder(zipPath, out, password); err != nil {
+		t.Fatalf("decryptZipFolder: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(folder, "b.txt"))
+	if err != nil {
+		t.Fatalf("reading decrypted b.txt: %v", err)
+	}
+	if string(got) != "hello from b, a bit longer this time" {
+		t.Errorf("b.txt = %q, want %q", got, "hello from b, a bit longer this time")
+	}
+}
+
+func TestDecryptZipFolderWrongPassword(t *testing.T) {
+	folder := setUpZipCryptoFolder(t)
+	zipPath := folder + ".zip"
+
+	if err := encryptZipFolder(folder, zipPath, []byte("right password"), zipCryptoAES); err != nil {
+		t.Fatalf("encryptZipFolder: %v", err)
+	}
+
+	err := decryptZipFolder(zipPath, filepath.Dir(folder), []byte("wrong password"))
+	if err == nil {
+		t.Fatal("expected an error decrypting with the wrong password")
+	}
+}
+
+func TestWinzipCTRCryptRoundTrips(t *testing.T) {
+	key := make([]byte, aesKeyLen)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	plain := []byte("some plaintext spanning more than one 16-byte AES block, for good measure")
+
+	ciphertext, err := winzipCTRCrypt(key, plain)
+	if err != nil {
+		t.Fatalf("winzipCTRCrypt encrypt: %v", err)
+	}
+	recovered, err := winzipCTRCrypt(key, ciphertext)
+	if err != nil {
+		t.Fatalf("winzipCTRCrypt decrypt: %v", err)
+	}
+	if string(recovered) != string(plain) {
+		t.Errorf("recovered = %q, want %q", recovered, plain)
+	}
+}
+
+func TestTraditionalCipherRoundTrips(t *testing.T) {
+	password := []byte("zippassword")
+	plain := []byte("zipcrypto plaintext payload")
+
+	sealed, err := sealTraditionalEntry(plain, password, 0xdeadbeef)
+	if err != nil {
+		t.Fatalf("sealTraditionalEntry: %v", err)
+	}
+	opened, err := openTraditionalEntry(sealed, password, byte(uint32(0xdeadbeef)>>24))
+	if err != nil {
+		t.Fatalf("openTraditionalEntry: %v", err)
+	}
+	if string(opened) != string(plain) {
+		t.Errorf("opened = %q, want %q", opened, plain)
+	}
+
+	if _, err := openTraditionalEntry(sealed, []byte("wrong"), byte(uint32(0xdeadbeef)>>24)); err == nil {
+		t.Error("expected an error opening with the wrong password")
+	}
+}
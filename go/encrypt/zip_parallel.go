@@ -0,0 +1,279 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ZipOptions tunes zipFolder's parallel deflate pipeline.
+type ZipOptions struct {
+	Workers           int
+	ParallelThreshold int64
+}
+
+const (
+	defaultParallelThreshold = 6 * 1024 * 1024 // files above this size are split into blocks
+	zipBlockSize             = 1 << 20         // 1 MiB
+)
+
+func (o ZipOptions) withDefaults() ZipOptions {
+	if o.Workers <= 0 {
+		o.Workers = runtime.NumCPU()
+	}
+	if o.ParallelThreshold <= 0 {
+		o.ParallelThreshold = defaultParallelThreshold
+	}
+	return o
+}
+
+// pathMapping is a single walked entry waiting to be compressed.
+type pathMapping struct {
+	order   int
+	path    string
+	relName string
+	info    os.FileInfo
+}
+
+// zippedEntry is a completed job: a header ready for the archive and, for
+// regular files, its already-deflated bytes.
+type zippedEntry struct {
+	order  int
+	header *zip.FileHeader
+	data   []byte
+	err    error
+}
+
+func zipFolder(folder string, zipFileName string) error {
+	return zipFolderWithOptions(folder, zipFileName, ZipOptions{})
+}
+
+// zipFolderWithOptions walks folder and deflates its files across a worker
+// pool (the dispatcher/worker pattern used elsewhere in this repo), then
+// replays the completed entries into the zip.Writer in walk order so the
+// resulting archive is byte-deterministic regardless of which worker
+// finishes first.
+func zipFolderWithOptions(folder string, zipFileName string, opts ZipOptions) error {
+	opts = opts.withDefaults()
+
+	zipFile, err := os.Create(zipFileName)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+	zipWriter := zip.NewWriter(zipFile)
+	defer zipWriter.Close()
+
+	var mappings []pathMapping
+	err = filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relName, err := filepath.Rel(filepath.Dir(folder), path)
+		if err != nil {
+			return err
+		}
+		mappings = append(mappings, pathMapping{order: len(mappings), path: path, relName: relName, info: info})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	jobs := make(chan pathMapping, opts.Workers)
+	results := make(chan zippedEntry, opts.Workers)
+
+	var wg sync.WaitGroup
+	wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- deflateEntry(job, opts)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		for _, m := range mappings {
+			jobs <- m
+		}
+		close(jobs)
+	}()
+
+	return writeZipEntriesInOrder(zipWriter, results, len(mappings))
+}
+
+// deflateEntry compresses a single walked entry. Directories carry no
+// content; files above opts.ParallelThreshold are split into blocks and
+// deflated in parallel, smaller files are deflated in a single pass.
+func deflateEntry(job pathMapping, opts ZipOptions) zippedEntry {
+	header, err := zip.FileInfoHeader(job.info)
+	if err != nil {
+		return zippedEntry{order: job.order, err: err}
+	}
+	header.Name = job.relName
+
+	if job.info.IsDir() {
+		header.Name += "/"
+		return zippedEntry{order: job.order, header: header}
+	}
+	header.Method = zip.Deflate
+
+	content, err := os.ReadFile(job.path)
+	if err != nil {
+		return zippedEntry{order: job.order, err: err}
+	}
+
+	var compressed []byte
+	if int64(len(content)) > opts.ParallelThreshold {
+		compressed, err = deflateBlocksParallel(content, zipBlockSize, opts.Workers)
+	} else {
+		compressed, err = deflateBlock(content, true)
+	}
+	if err != nil {
+		return zippedEntry{order: job.order, err: err}
+	}
+
+	header.CRC32 = crc32.ChecksumIEEE(content)
+	header.CompressedSize64 = uint64(len(compressed))
+	header.UncompressedSize64 = uint64(len(content))
+
+	return zippedEntry{order: job.order, header: header, data: compressed}
+}
+
+// deflateBlock compresses data as a single, independent deflate stream. When
+// final is false the stream is sync-flushed rather than closed, so its
+// output can be concatenated with the next block's: deflate only requires a
+// single final block at the very end of the whole stream, and a sync-flushed
+// writer never back-references data outside its own block, so independently
+// compressed blocks concatenate into one valid stream (at a small cost to
+// the compression ratio, since each block starts with an empty window).
+func deflateBlock(data []byte, final bool) ([]byte, error) {
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return nil, err
+	}
+	if final {
+		if err := fw.Close(); err != nil {
+			return nil, err
+		}
+	} else if err := fw.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// deflateBlocksParallel splits data into blockSize chunks, compresses them
+// concurrently (bounded by workers), and concatenates the results in order.
+func deflateBlocksParallel(data []byte, blockSize, workers int) ([]byte, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var blocks [][]byte
+	for offset := 0; offset < len(data); offset += blockSize {
+		end := offset + blockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		blocks = append(blocks, data[offset:end])
+	}
+	if len(blocks) == 0 {
+		blocks = [][]byte{{}}
+	}
+
+	compressed := make([][]byte, len(blocks))
+	errs := make([]error, len(blocks))
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, block := range blocks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, block []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			compressed[i], errs[i] = deflateBlock(block, i == len(blocks)-1)
+		}(i, block)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	for _, c := range compressed {
+		out.Write(c)
+	}
+	return out.Bytes(), nil
+}
+
+// inflateAll reads a complete (possibly block-concatenated) deflate stream
+// into memory, reversing deflateBlock/deflateBlocksParallel.
+func inflateAll(compressed []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// writeZipEntriesInOrder buffers completed entries until the next expected
+// walk order is available, then appends it to the archive.
+func writeZipEntriesInOrder(zipWriter *zip.Writer, results <-chan zippedEntry, total int) error {
+	pending := make(map[int]zippedEntry)
+	next := 0
+	written := 0
+	for entry := range results {
+		if entry.err != nil {
+			return entry.err
+		}
+		pending[entry.order] = entry
+		for {
+			e, ok := pending[next]
+			if !ok {
+				break
+			}
+			if err := writeZipEntry(zipWriter, e); err != nil {
+				return err
+			}
+			delete(pending, next)
+			next++
+			written++
+		}
+	}
+	if written != total {
+		return fmt.Errorf("zipFolder: wrote %d entries, expected %d", written, total)
+	}
+	return nil
+}
+
+func writeZipEntry(zipWriter *zip.Writer, e zippedEntry) error {
+	if e.header.Method != zip.Deflate {
+		_, err := zipWriter.CreateHeader(e.header)
+		return err
+	}
+	w, err := zipWriter.CreateRaw(e.header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(e.data)
+	return err
+}
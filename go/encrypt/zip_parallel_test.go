@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestZipFolderWithOptionsSplitsLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	srcFolder := filepath.Join(dir, "bigfolder")
+	if err := os.MkdirAll(srcFolder, 0755); err != nil {
+		t.Fatalf("creating source folder: %v", err)
+	}
+
+	// bigger than a tiny threshold, and bigger than one zipBlockSize, so the
+	// parallel block path runs across more than one block.
+	big := make([]byte, zipBlockSize*2+512)
+	for i := range big {
+		big[i] = byte(i % 251)
+	}
+	if err := os.WriteFile(filepath.Join(srcFolder, "big.bin"), big, 0644); err != nil {
+		t.Fatalf("writing big file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcFolder, "small.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("writing small file: %v", err)
+	}
+
+	zipFileName := filepath.Join(dir, "bigfolder.zip")
+	opts := ZipOptions{Workers: 3, ParallelThreshold: 1024}
+	if err := zipFolderWithOptions(srcFolder, zipFileName, opts); err != nil {
+		t.Fatalf("zipFolderWithOptions: %v", err)
+	}
+
+	unzipped := filepath.Join(dir, "unzipped")
+	if err := unzipFolder(zipFileName, unzipped); err != nil {
+		t.Fatalf("unzipFolder: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(unzipped, "bigfolder", "big.bin"))
+	if err != nil {
+		t.Fatalf("reading round-tripped big file: %v", err)
+	}
+	if len(got) != len(big) {
+		t.Fatalf("round-tripped length = %d, want %d", len(got), len(big))
+	}
+	for i := range big {
+		if got[i] != big[i] {
+			t.Fatalf("round-tripped content differs at byte %d", i)
+		}
+	}
+
+	gotSmall, err := os.ReadFile(filepath.Join(unzipped, "bigfolder", "small.txt"))
+	if err != nil {
+		t.Fatalf("reading round-tripped small file: %v", err)
+	}
+	if string(gotSmall) != "hello" {
+		t.Fatalf("small file = %q, want %q", gotSmall, "hello")
+	}
+}
+
+func TestDeflateBlocksParallelRoundTrips(t *testing.T) {
+	data := make([]byte, zipBlockSize*3+17)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	compressed, err := deflateBlocksParallel(data, zipBlockSize, 4)
+	if err != nil {
+		t.Fatalf("deflateBlocksParallel: %v", err)
+	}
+
+	decompressed, err := inflateAll(compressed)
+	if err != nil {
+		t.Fatalf("inflating concatenated blocks: %v", err)
+	}
+	if len(decompressed) != len(data) {
+		t.Fatalf("decompressed length = %d, want %d", len(decompressed), len(data))
+	}
+	for i := range data {
+		if decompressed[i] != data[i] {
+			t.Fatalf("decompressed content differs at byte %d", i)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultCacheTTL is how long a cached response is served before doRequest
+// treats it as stale and falls back to the network.
+const defaultCacheTTL = 15 * time.Minute
+
+// JiraCache is a pluggable store for raw Jira API response bodies, keyed by
+// a caller-supplied key. Implementations decide how (and whether) entries
+// expire on disk; Get reports whether the entry is still fresh.
+type JiraCache interface {
+	// Get returns the cached body and the time it was stored, and reports
+	// whether key was found and is still fresh.
+	Get(key string) (body []byte, storedAt time.Time, ok bool)
+	// Put stores body under key, recording ttl so Get can treat the entry
+	// as stale once ttl has passed.
+	Put(key string, body []byte, ttl time.Duration) error
+	// Invalidate discards every entry whose endpoint starts with prefix.
+	Invalidate(prefix string) error
+}
+
+// FileCache is a JiraCache backed by the filesystem, sharding entries two
+// levels deep by key hash (`<root>/<endpoint>/<hash[:3]>/<hash[3:]>.json.gz`)
+// so no single directory accumulates too many files. Entries are
+// gzip-compressed since Jira responses are JSON and compress well.
+type FileCache struct {
+	Root string
+}
+
+// NewFileCache creates a FileCache rooted at dir, creating it if necessary.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("cache: creating root %s: %w", dir, err)
+	}
+	return &FileCache{Root: dir}, nil
+}
+
+// cacheKey derives the stable key doRequest uses to address a cached
+// response: method, endpoint, and params sorted by name so map iteration
+// order can't change the key.
+func cacheKey(method, endpoint string, params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(method)
+	b.WriteByte(' ')
+	b.WriteString(endpoint)
+	for _, k := range names {
+		fmt.Fprintf(&b, "&%s=%s", k, params[k])
+	}
+	return b.String()
+}
+
+// path returns the on-disk path for key, sharding two levels deep by the
+// hex-encoded sha256 of key so no single directory holds every entry for an
+// endpoint.
+func (c *FileCache) path(key string) string {
+	endpoint, hash := splitCacheKey(key)
+	return filepath.Join(c.Root, endpoint, hash[:3], hash[3:]+".json.gz")
+}
+
+// splitCacheKey separates the endpoint (used only to keep cache entries for
+// different endpoints in different directories) from the hash of the full
+// key (method, endpoint, and params) that actually identifies the entry.
+func splitCacheKey(key string) (endpoint, hash string) {
+	endpoint = key
+	if i := strings.IndexByte(key, ' '); i >= 0 {
+		rest := key[i+1:]
+		if j := strings.IndexByte(rest, '&'); j >= 0 {
+			endpoint = rest[:j]
+		} else {
+			endpoint = rest
+		}
+	}
+	endpoint = strings.Trim(endpoint, "/")
+	if endpoint == "" {
+		endpoint = "_"
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	hash = hex.EncodeToString(sum[:])
+	return endpoint, hash
+}
+
+// Get reads the cached body for key, if present, and reports a miss once the
+// ttl recorded by Put has passed (a zero ttl never expires).
+func (c *FileCache) Get(key string) ([]byte, time.Time, bool) {
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	defer gz.Close()
+
+	var header [16]byte
+	if _, err := io.ReadFull(gz, header[:]); err != nil {
+		return nil, time.Time{}, false
+	}
+	storedAtUnix := int64(beUint64(header[:8]))
+	ttl := time.Duration(beUint64(header[8:]))
+	storedAt := time.Unix(storedAtUnix, 0).UTC()
+
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+
+	if ttl > 0 && time.Since(storedAt) > ttl {
+		return nil, time.Time{}, false
+	}
+	return body, storedAt, true
+}
+
+// Put writes body under key, gzip-compressed, recording the current time and
+// ttl in a small fixed-size header so Get can judge freshness on its own.
+func (c *FileCache) Put(key string, body []byte, ttl time.Duration) error {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cache: creating shard dir for %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("cache: creating temp file for %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	var header [16]byte
+	putBeUint64(header[:8], uint64(time.Now().UTC().Unix()))
+	putBeUint64(header[8:], uint64(ttl))
+	if _, err := gz.Write(header[:]); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := gz.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Invalidate removes every cached entry for an endpoint whose name starts
+// with prefix. Endpoints, not raw keys, are the unit of invalidation since
+// that's the granularity callers reason about (e.g. "issue/PROJ-123").
+func (c *FileCache) Invalidate(prefix string) error {
+	prefix = strings.Trim(prefix, "/")
+	dir := c.Root
+	if i := strings.LastIndexByte(prefix, '/'); i >= 0 {
+		dir = filepath.Join(c.Root, filepath.FromSlash(prefix[:i]))
+		prefix = prefix[i+1:]
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func beUint64(b []byte) uint64 {
+	var v uint64
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return v
+}
+
+func putBeUint64(b []byte, v uint64) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
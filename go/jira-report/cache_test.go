@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCachePutThenGetRoundTrips(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	key := cacheKey("GET", "issue/PROJ-1", map[string]string{"fields": "summary"})
+	if err := cache.Put(key, []byte(`{"key":"PROJ-1"}`), time.Hour); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, storedAt, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("Get: expected a hit")
+	}
+	if string(body) != `{"key":"PROJ-1"}` {
+		t.Errorf("Get body = %q, want %q", body, `{"key":"PROJ-1"}`)
+	}
+	if time.Since(storedAt) > time.Minute {
+		t.Errorf("storedAt = %v, want roughly now", storedAt)
+	}
+}
+
+func TestFileCacheGetMissReportsNotOK(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	if _, _, ok := cache.Get(cacheKey("GET", "issue/PROJ-1", nil)); ok {
+		t.Fatal("Get: expected a miss on an empty cache")
+	}
+}
+
+func TestFileCacheGetExpiresPastTTL(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	key := cacheKey("GET", "search", map[string]string{"jql": "project = PROJ"})
+	if err := cache.Put(key, []byte(`{}`), time.Millisecond); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, ok := cache.Get(key); ok {
+		t.Fatal("Get: expected an expired entry to miss")
+	}
+}
+
+func TestFileCachePathShardsTwoLevelsDeep(t *testing.T) {
+	cache := &FileCache{Root: "/cache-root"}
+	key := cacheKey("GET", "issue/PROJ-1", nil)
+
+	endpoint, hash := splitCacheKey(key)
+	want := filepath.Join(cache.Root, endpoint, hash[:3], hash[3:]+".json.gz")
+	if got := cache.path(key); got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+}
+
+func TestFileCacheInvalidateRemovesMatchingEndpoint(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	issueKey := cacheKey("GET", "issue/PROJ-1", nil)
+	searchKey := cacheKey("GET", "search", map[string]string{"jql": "project = PROJ"})
+	if err := cache.Put(issueKey, []byte(`{}`), time.Hour); err != nil {
+		t.Fatalf("Put issue: %v", err)
+	}
+	if err := cache.Put(searchKey, []byte(`{}`), time.Hour); err != nil {
+		t.Fatalf("Put search: %v", err)
+	}
+
+	if err := cache.Invalidate("issue"); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+
+	if _, _, ok := cache.Get(issueKey); ok {
+		t.Error("Get issue: expected a miss after invalidation")
+	}
+	if _, _, ok := cache.Get(searchKey); !ok {
+		t.Error("Get search: expected the unrelated endpoint to survive invalidation")
+	}
+}
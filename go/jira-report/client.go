@@ -8,10 +8,25 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// AuthMode selects how JiraClient signs outgoing requests.
+type AuthMode int
+
+const (
+	// AuthBasic sends Basic auth with email:token (Jira Cloud).
+	AuthBasic AuthMode = iota
+	// AuthPAT sends a Bearer personal access token (Jira Server/Data Center).
+	AuthPAT
+	// AuthOAuth1 signs requests with RSA-SHA1 using a three-legged OAuth 1.0a access token.
+	AuthOAuth1
+	// AuthOAuth2 sends a Bearer OAuth 2.0 access token.
+	AuthOAuth2
+)
+
 // JiraClient is a simple Jira REST API client
 type JiraClient struct {
 	Server     string
@@ -19,7 +34,20 @@ type JiraClient struct {
 	APIToken   string
 	APIVersion string
 	IsCloud    bool
+	AuthMode   AuthMode
+	OAuth1     *OAuth1Credentials
+	OAuth2     *OAuth2Credentials
 	HTTPClient *http.Client
+	// RateLimit bounds how often doRequest issues requests against Server.
+	// NewJiraClient defaults it to defaultRateLimit requests/second.
+	RateLimit *RateLimiter
+	// Cache, when set, serves fresh GET responses from disk instead of
+	// hitting Server. NewJiraClient leaves it nil; GetJiraClient wires one
+	// up from JIRA_CACHE_DIR.
+	Cache JiraCache
+	// CacheTTL is how long a cached GET response is considered fresh.
+	// NewJiraClient defaults it to defaultCacheTTL.
+	CacheTTL time.Duration
 }
 
 // NewJiraClient creates a new Jira client
@@ -28,11 +56,13 @@ func NewJiraClient(server, apiToken, email string) (*JiraClient, error) {
 	isCloud := strings.Contains(strings.ToLower(server), ".atlassian.net")
 
 	apiVersion := "2"
+	authMode := AuthPAT
 	if isCloud {
 		if email == "" {
 			return nil, fmt.Errorf("JIRA_EMAIL is required for Jira Cloud authentication")
 		}
 		apiVersion = "3"
+		authMode = AuthBasic
 		logDebug("Using Jira Cloud authentication (API v%s)", apiVersion)
 	} else {
 		logDebug("Using Jira Server/Data Center authentication (API v%s)", apiVersion)
@@ -44,13 +74,45 @@ func NewJiraClient(server, apiToken, email string) (*JiraClient, error) {
 		APIToken:   apiToken,
 		APIVersion: apiVersion,
 		IsCloud:    isCloud,
+		AuthMode:   authMode,
 		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		RateLimit:  NewRateLimiter(defaultRateLimit),
+		CacheTTL:   defaultCacheTTL,
 	}, nil
 }
 
-// doRequest makes an authenticated request to the Jira API
+// apiRoot returns the base URL doRequest builds REST paths against. For
+// AuthOAuth2 with a resolved cloud ID, that's api.atlassian.com's 3LO proxy
+// (which addresses Jira Cloud sites by cloud ID, not hostname); every other
+// auth mode talks to the Jira server directly.
+func (c *JiraClient) apiRoot() string {
+	if c.AuthMode == AuthOAuth2 && c.OAuth2 != nil && c.OAuth2.CloudID != "" {
+		return fmt.Sprintf("https://api.atlassian.com/ex/jira/%s", c.OAuth2.CloudID)
+	}
+	return c.Server
+}
+
+// maxRetries bounds how many times doRequest retries a throttled or
+// temporarily-unavailable request before giving up.
+const maxRetries = 5
+
+// doRequest makes an authenticated request to the Jira API. It is the single
+// choke point for outgoing requests: it waits on c.RateLimit before sending,
+// and retries on HTTP 429/503, honoring a Retry-After header when present and
+// falling back to exponential backoff otherwise.
 func (c *JiraClient) doRequest(method, endpoint string, params map[string]string) ([]byte, error) {
-	baseURL := fmt.Sprintf("%s/rest/api/%s/%s", c.Server, c.APIVersion, strings.TrimLeft(endpoint, "/"))
+	log := rootLogger.With("component", "jira-client")
+
+	var key string
+	if method == http.MethodGet && c.Cache != nil {
+		key = cacheKey(method, endpoint, params)
+		if body, storedAt, ok := c.Cache.Get(key); ok {
+			log.Debug().Str("endpoint", endpoint).Str("stored_at", storedAt.Format(time.RFC3339)).Msg("cache hit")
+			return body, nil
+		}
+	}
+
+	baseURL := fmt.Sprintf("%s/rest/api/%s/%s", c.apiRoot(), c.APIVersion, strings.TrimLeft(endpoint, "/"))
 
 	// Add query params
 	if len(params) > 0 {
@@ -61,44 +123,83 @@ func (c *JiraClient) doRequest(method, endpoint string, params map[string]string
 		baseURL += "?" + values.Encode()
 	}
 
-	logDebug("Request: %s %s", method, baseURL)
+	for attempt := 0; ; attempt++ {
+		c.RateLimit.Wait()
 
-	req, err := http.NewRequest(method, baseURL, nil)
-	if err != nil {
-		return nil, err
-	}
+		log.Debug().Str("method", method).Str("url", baseURL).Int("attempt", attempt+1).Msg("sending request")
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+		req, err := http.NewRequest(method, baseURL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	if c.IsCloud {
-		// Basic auth with email:token
-		auth := base64.StdEncoding.EncodeToString([]byte(c.Email + ":" + c.APIToken))
-		req.Header.Set("Authorization", "Basic "+auth)
-	} else {
-		// Bearer token (PAT)
-		req.Header.Set("Authorization", "Bearer "+c.APIToken)
-	}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		switch c.AuthMode {
+		case AuthOAuth1:
+			header, err := signOAuth1Request(method, baseURL, c.OAuth1)
+			if err != nil {
+				return nil, fmt.Errorf("oauth1: signing request: %w", err)
+			}
+			req.Header.Set("Authorization", header)
+		case AuthOAuth2:
+			if err := c.EnsureFreshToken(); err != nil {
+				return nil, fmt.Errorf("oauth2: refreshing token: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+c.OAuth2.AccessToken)
+		case AuthPAT:
+			req.Header.Set("Authorization", "Bearer "+c.APIToken)
+		default: // AuthBasic
+			auth := base64.StdEncoding.EncodeToString([]byte(c.Email + ":" + c.APIToken))
+			req.Header.Set("Authorization", "Basic "+auth)
+		}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
 
-	logDebug("Response: %d", resp.StatusCode)
+		log.Debug().Int("status", resp.StatusCode).Msg("received response")
 
-	if resp.StatusCode >= 400 {
-		logError("API error: %d - %s", resp.StatusCode, truncate(string(body), 500))
-		return nil, fmt.Errorf("API error: %d", resp.StatusCode)
+		if (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) && attempt < maxRetries {
+			wait := retryDelay(resp.Header.Get("Retry-After"), attempt)
+			log.Debug().Int("status", resp.StatusCode).Dur("wait", wait).Msg("throttled, retrying")
+			time.Sleep(wait)
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			log.Error().Int("status", resp.StatusCode).Str("body", truncate(string(body), 500)).Msg("API error")
+			return nil, fmt.Errorf("API error: %d", resp.StatusCode)
+		}
+
+		if key != "" {
+			if err := c.Cache.Put(key, body, c.CacheTTL); err != nil {
+				log.Warn().Err(err).Str("endpoint", endpoint).Msg("failed to write cache entry")
+			}
+		}
+
+		return body, nil
 	}
+}
 
-	return body, nil
+// retryDelay computes how long to wait before retrying a throttled request.
+// It honors a Retry-After header (seconds) when present and parseable, and
+// otherwise falls back to exponential backoff based on the attempt number.
+func retryDelay(retryAfter string, attempt int) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return time.Duration(1<<uint(attempt)) * time.Second
 }
 
 // Get makes a GET request and returns JSON data
@@ -131,8 +232,9 @@ func (c *JiraClient) GetList(endpoint string, params map[string]string) ([]map[s
 	return result, nil
 }
 
-// GetIssue fetches a single issue by key
-func (c *JiraClient) GetIssue(issueKey string) (map[string]any, error) {
+// issueFields builds the fields list shared by GetIssue and
+// GetIssueWithChangelog.
+func issueFields() string {
 	fields := "summary,status,assignee,priority,created,updated,subtasks,issuelinks"
 	// Add custom field IDs
 	for _, id := range customFields {
@@ -140,7 +242,18 @@ func (c *JiraClient) GetIssue(issueKey string) (map[string]any, error) {
 			fields += "," + id
 		}
 	}
-	return c.Get(fmt.Sprintf("issue/%s", issueKey), map[string]string{"fields": fields})
+	return fields
+}
+
+// GetIssue fetches a single issue by key
+func (c *JiraClient) GetIssue(issueKey string) (map[string]any, error) {
+	return c.Get(fmt.Sprintf("issue/%s", issueKey), map[string]string{"fields": issueFields()})
+}
+
+// GetIssueWithChangelog is GetIssue, but also expands the issue's changelog
+// so ExtractChangelog has field-transition history to work with.
+func (c *JiraClient) GetIssueWithChangelog(issueKey string) (map[string]any, error) {
+	return c.Get(fmt.Sprintf("issue/%s", issueKey), map[string]string{"fields": issueFields(), "expand": "changelog"})
 }
 
 // LoadCustomFields resolves custom field names to IDs
@@ -164,19 +277,7 @@ func (c *JiraClient) LoadCustomFields(fieldNames map[string]string) error {
 
 // SearchIssues searches for issues using JQL with pagination
 func (c *JiraClient) SearchIssues(jql string, maxResults int) ([]map[string]any, error) {
-	fields := "summary,status,assignee,priority,created,updated"
-
-	// Load custom fields first
-	if err := c.LoadCustomFields(customFields); err != nil {
-		logWarning("Could not load custom fields: %v", err)
-	}
-
-	// Add custom field IDs
-	for _, id := range customFields {
-		if id != "" {
-			fields += "," + id
-		}
-	}
+	fields := c.searchFields()
 
 	var allIssues []map[string]any
 	startAt := 0
@@ -232,6 +333,30 @@ func (c *JiraClient) TestConnection() bool {
 	return true
 }
 
+// noCache disables the on-disk response cache regardless of JIRA_CACHE_DIR.
+// main sets it from the --no-cache flag before calling GetJiraClient.
+var noCache bool
+
+// applyCache wires a FileCache into client from JIRA_CACHE_DIR, unless
+// noCache is set. A cache root that can't be created is a warning, not a
+// fatal error: the tool still works, just without caching.
+func applyCache(client *JiraClient) {
+	if noCache {
+		return
+	}
+	dir := os.Getenv("JIRA_CACHE_DIR")
+	if dir == "" {
+		return
+	}
+	cache, err := NewFileCache(dir)
+	if err != nil {
+		logWarning("Could not set up response cache at %s: %v", dir, err)
+		return
+	}
+	client.Cache = cache
+	logDebug("Caching GET responses under %s (ttl %s)", dir, client.CacheTTL)
+}
+
 // GetJiraClient creates a Jira client from environment variables
 func GetJiraClient() (*JiraClient, error) {
 	server := os.Getenv("JIRA_SERVER")
@@ -242,6 +367,69 @@ func GetJiraClient() (*JiraClient, error) {
 		return nil, fmt.Errorf("JIRA_SERVER environment variable is not set.\nExample: export JIRA_SERVER=https://mycompany.atlassian.net")
 	}
 
+	authMode := strings.ToLower(os.Getenv("JIRA_AUTH_MODE"))
+	switch authMode {
+	case "oauth1":
+		consumerKey := os.Getenv("JIRA_OAUTH_CONSUMER_KEY")
+		privateKeyPath := os.Getenv("JIRA_OAUTH_PRIVATE_KEY_FILE")
+		if consumerKey == "" || privateKeyPath == "" {
+			return nil, fmt.Errorf("JIRA_OAUTH_CONSUMER_KEY and JIRA_OAUTH_PRIVATE_KEY_FILE are required when JIRA_AUTH_MODE=oauth1")
+		}
+		client, err := NewJiraClientOAuth(server, consumerKey, privateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		applyCache(client)
+		if !client.TestConnection() {
+			return nil, fmt.Errorf("failed to connect to Jira using OAuth 1.0a. Check your consumer key, private key, and server URL")
+		}
+		return client, nil
+	case "oauth2":
+		clientID := os.Getenv("JIRA_OAUTH_CLIENT_ID")
+		clientSecret := os.Getenv("JIRA_OAUTH_CLIENT_SECRET")
+		if clientID == "" || clientSecret == "" {
+			return nil, fmt.Errorf("JIRA_OAUTH_CLIENT_ID and JIRA_OAUTH_CLIENT_SECRET are required when JIRA_AUTH_MODE=oauth2")
+		}
+		redirectURL := os.Getenv("JIRA_OAUTH_REDIRECT_URL")
+		if redirectURL == "" {
+			redirectURL = "http://localhost:8976/callback"
+		}
+		client, err := NewJiraClientOAuth2(server, clientID, clientSecret, redirectURL)
+		if err != nil {
+			return nil, err
+		}
+		applyCache(client)
+		if !client.TestConnection() {
+			return nil, fmt.Errorf("failed to connect to Jira using OAuth 2.0. Check your client ID/secret and re-authorize")
+		}
+		return client, nil
+	case "basic", "pat":
+		if apiToken == "" {
+			return nil, fmt.Errorf("JIRA_API_TOKEN environment variable is not set")
+		}
+		if authMode == "basic" && email == "" {
+			return nil, fmt.Errorf("JIRA_EMAIL environment variable is required when JIRA_AUTH_MODE=basic")
+		}
+		client, err := NewJiraClient(server, apiToken, email)
+		if err != nil {
+			return nil, err
+		}
+		if authMode == "basic" {
+			client.AuthMode = AuthBasic
+		} else {
+			client.AuthMode = AuthPAT
+		}
+		applyCache(client)
+		if !client.TestConnection() {
+			return nil, fmt.Errorf("failed to connect to Jira using %s auth. Check your credentials and server URL", authMode)
+		}
+		return client, nil
+	case "":
+		// fall through to the auto-detected basic/PAT behavior below
+	default:
+		return nil, fmt.Errorf("unknown JIRA_AUTH_MODE %q (want basic|pat|oauth1|oauth2)", authMode)
+	}
+
 	if apiToken == "" {
 		isCloud := strings.Contains(strings.ToLower(server), ".atlassian.net")
 		if isCloud {
@@ -259,6 +447,7 @@ func GetJiraClient() (*JiraClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	applyCache(client)
 
 	if !client.TestConnection() {
 		if !isCloud {
@@ -0,0 +1,103 @@
+package main
+
+import "sync"
+
+// defaultFetchConcurrency is how many goroutines FetchIssuesConcurrent uses
+// when the caller doesn't ask for a specific number.
+const defaultFetchConcurrency = 4
+
+// issueFetchJob is one GetIssueDetails call dispatched across the worker pool.
+type issueFetchJob struct {
+	IssueKey      string
+	ParentKey     string
+	ParentSummary string
+	// WithChangelog routes the job through GetIssueDetailsWithChangelog
+	// instead of GetIssueDetails, for RenderChangelogSection.
+	WithChangelog bool
+}
+
+// indexedFetchResult tags a fetch outcome with its position in the original
+// job slice so results can be reassembled in submission order.
+type indexedFetchResult struct {
+	index int
+	data  *IssueData
+	err   error
+}
+
+// FetchIssuesConcurrent dispatches GetIssueDetails across a fixed pool of
+// worker goroutines: jobs are handed out over a channel, workers pull from
+// it until it's closed, and results are reassembled in the order jobs were
+// submitted in (mirroring the dispatcher/worker pattern in the repo's thread
+// pool, adapted here to GetIssueDetails and typed around IssueData). A job
+// whose fetch errors is dropped from the returned issues, same as the
+// sequential callers this replaces (GetIssueDetails already logs the
+// failure), but is counted in the returned failed count so callers that
+// need a complete run - like GenerateReport deciding whether to advance its
+// watermark - can tell a partial result from a full one. workers defaults to
+// defaultFetchConcurrency when <= 0.
+func FetchIssuesConcurrent(client *JiraClient, jobs []issueFetchJob, workers int) (issues []IssueData, failed int) {
+	if len(jobs) == 0 {
+		return nil, 0
+	}
+	if workers <= 0 {
+		workers = defaultFetchConcurrency
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	type indexedJob struct {
+		index int
+		job   issueFetchJob
+	}
+
+	jobCh := make(chan indexedJob)
+	resultCh := make(chan indexedFetchResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ij := range jobCh {
+				var data *IssueData
+				var err error
+				if ij.job.WithChangelog {
+					data, err = GetIssueDetailsWithChangelog(client, ij.job.IssueKey, ij.job.ParentKey, ij.job.ParentSummary)
+				} else {
+					data, err = GetIssueDetails(client, ij.job.IssueKey, ij.job.ParentKey, ij.job.ParentSummary)
+				}
+				resultCh <- indexedFetchResult{index: ij.index, data: data, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for i, job := range jobs {
+			jobCh <- indexedJob{index: i, job: job}
+		}
+		close(jobCh)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	ordered := make([]*IssueData, len(jobs))
+	for r := range resultCh {
+		if r.err == nil {
+			ordered[r.index] = r.data
+		} else {
+			failed++
+		}
+	}
+
+	issues = make([]IssueData, 0, len(jobs))
+	for _, data := range ordered {
+		if data != nil {
+			issues = append(issues, *data)
+		}
+	}
+	return issues, failed
+}
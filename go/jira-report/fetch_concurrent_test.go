@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newIssueTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path[len("/rest/api/2/issue/"):]
+		fmt.Fprintf(w, `{"key":"%s","fields":{"summary":"summary for %s","status":{"name":"Open"}}}`, key, key)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestFetchIssuesConcurrentPreservesOrder(t *testing.T) {
+	server := newIssueTestServer(t)
+	defer server.Close()
+
+	client := newTestClient(server)
+	jobs := make([]issueFetchJob, 20)
+	for i := range jobs {
+		jobs[i] = issueFetchJob{IssueKey: fmt.Sprintf("PROJ-%d", i)}
+	}
+
+	issues, failed := FetchIssuesConcurrent(client, jobs, 4)
+	if len(issues) != len(jobs) {
+		t.Fatalf("got %d issues, want %d", len(issues), len(jobs))
+	}
+	if failed != 0 {
+		t.Errorf("failed = %d, want 0", failed)
+	}
+	for i, issue := range issues {
+		want := fmt.Sprintf("PROJ-%d", i)
+		if issue.Key != want {
+			t.Errorf("issues[%d].Key = %q, want %q", i, issue.Key, want)
+		}
+	}
+}
+
+func TestFetchIssuesConcurrentEmptyJobsReturnsNil(t *testing.T) {
+	client := &JiraClient{}
+	issues, failed := FetchIssuesConcurrent(client, nil, 4)
+	if issues != nil {
+		t.Errorf("FetchIssuesConcurrent(nil) issues = %v, want nil", issues)
+	}
+	if failed != 0 {
+		t.Errorf("FetchIssuesConcurrent(nil) failed = %d, want 0", failed)
+	}
+}
+
+func TestFetchIssuesConcurrentDefaultsWorkers(t *testing.T) {
+	server := newIssueTestServer(t)
+	defer server.Close()
+
+	client := newTestClient(server)
+	issues, failed := FetchIssuesConcurrent(client, []issueFetchJob{{IssueKey: "PROJ-1"}}, 0)
+	if len(issues) != 1 || issues[0].Key != "PROJ-1" {
+		t.Errorf("FetchIssuesConcurrent with workers<=0 = %v, want one PROJ-1 issue", issues)
+	}
+	if failed != 0 {
+		t.Errorf("FetchIssuesConcurrent with workers<=0 failed = %d, want 0", failed)
+	}
+}
+
+func TestFetchIssuesConcurrentCountsFailures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path[len("/rest/api/2/issue/"):]
+		if key == "PROJ-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"key":"%s","fields":{"summary":"summary for %s","status":{"name":"Open"}}}`, key, key)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newTestClient(server)
+	jobs := []issueFetchJob{{IssueKey: "PROJ-0"}, {IssueKey: "PROJ-1"}, {IssueKey: "PROJ-2"}}
+
+	issues, failed := FetchIssuesConcurrent(client, jobs, 4)
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2 (PROJ-1 should have been dropped)", len(issues))
+	}
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoggerJSONOutputIsValid(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, LevelDebug, FormatJSON)
+	l.Info().Str("issue", "PROJ-1").Int("count", 3).Msg("fetched issues")
+
+	line := strings.TrimSpace(buf.String())
+	var record map[string]any
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		t.Fatalf("expected valid JSON line, got %q: %v", line, err)
+	}
+	if record["msg"] != "fetched issues" {
+		t.Errorf("msg = %v, want 'fetched issues'", record["msg"])
+	}
+	if record["issue"] != "PROJ-1" {
+		t.Errorf("issue = %v, want PROJ-1", record["issue"])
+	}
+}
+
+func TestLoggerLevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, LevelWarning, FormatText)
+	l.Debug().Msg("should be suppressed")
+	l.Info().Msg("should also be suppressed")
+	l.Warn().Msg("should appear")
+
+	out := buf.String()
+	if strings.Contains(out, "suppressed") {
+		t.Errorf("expected debug/info to be filtered out, got %q", out)
+	}
+	if !strings.Contains(out, "should appear") {
+		t.Errorf("expected warning to be logged, got %q", out)
+	}
+}
+
+func TestLoggerWithChildFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, LevelDebug, FormatJSON)
+	child := l.With("component", "dispatcher")
+	child.Info().Msg("started")
+
+	var record map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &record); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if record["component"] != "dispatcher" {
+		t.Errorf("component = %v, want dispatcher", record["component"])
+	}
+}
@@ -9,6 +9,12 @@
 //   - Emit a combined report for multiple issues or individual reports per issue.
 //   - Output to stdout or append/write to a specified markdown file.
 //   - Supports both Jira Cloud and Jira Server/Data Center.
+//   - Persist state between runs with --state-file and report what changed since the last run.
+//   - Structured logging via --log-format (text|json) and --log-level (error|warn|info|debug).
+//   - Render as markdown, HTML, JSON, CSV, or Slack Block Kit via --format, optionally posting
+//     Slack blocks directly to a webhook with --slack-webhook.
+//   - Authenticate with basic auth, a PAT, OAuth 1.0a (RSA-SHA1), or OAuth 2.0 3LO via
+//     JIRA_AUTH_MODE, with OAuth tokens cached locally and refreshed automatically.
 //
 // Configuration:
 //
@@ -39,7 +45,6 @@ import (
 	"fmt"
 	"os"
 	"regexp"
-	"sort"
 	"strings"
 	"time"
 )
@@ -98,6 +103,42 @@ type IssueData struct {
 	ParentURL     string
 	Trending      string
 	Emoji         string
+	// Changelog is only populated by GetIssueDetailsWithChangelog, for
+	// RenderChangelogSection to summarize transitions within a --since window.
+	Changelog []ChangelogEntry
+}
+
+// ChangelogEntry is one field transition recorded in a Jira issue's
+// changelog (expand=changelog), e.g. a status move or a reassignment.
+type ChangelogEntry struct {
+	Author     string
+	Created    string
+	Field      string
+	FromString string
+	ToString   string
+}
+
+// ExtractChangelog flattens an expanded issue's changelog histories into one
+// ChangelogEntry per changed field. It returns nil when the issue wasn't
+// fetched with expand=changelog.
+func ExtractChangelog(issue map[string]any) []ChangelogEntry {
+	histories := getMapList(getMap(issue, "changelog"), "histories")
+
+	var entries []ChangelogEntry
+	for _, h := range histories {
+		author := getString(getMap(h, "author"), "displayName")
+		created := getString(h, "created")
+		for _, item := range getMapList(h, "items") {
+			entries = append(entries, ChangelogEntry{
+				Author:     author,
+				Created:    created,
+				Field:      getString(item, "field"),
+				FromString: getString(item, "fromString"),
+				ToString:   getString(item, "toString"),
+			})
+		}
+	}
+	return entries
 }
 
 // ExtractIssueData extracts relevant data from a Jira issue API response
@@ -188,6 +229,7 @@ func ExtractIssueData(issue map[string]any, serverURL string, parentKey, parentS
 		ParentURL:     parentURL,
 		Trending:      trending,
 		Emoji:         emoji,
+		Changelog:     ExtractChangelog(issue),
 	}
 }
 
@@ -204,14 +246,29 @@ func GetIssueDetails(client *JiraClient, issueKey, parentKey, parentSummary stri
 	return &data, nil
 }
 
-// GetSubtasks fetches subtasks for a parent issue
-func GetSubtasks(client *JiraClient, parentKey, parentSummary string) []IssueData {
-	var subtasks []IssueData
+// GetIssueDetailsWithChangelog is GetIssueDetails, but also expands and
+// captures the issue's changelog for RenderChangelogSection.
+func GetIssueDetailsWithChangelog(client *JiraClient, issueKey, parentKey, parentSummary string) (*IssueData, error) {
+	logInfo("  - Fetching (with changelog): %s", issueKey)
+	issue, err := client.GetIssueWithChangelog(issueKey)
+	if err != nil {
+		logError("Failed to fetch issue %s: %v", issueKey, err)
+		return nil, err
+	}
+
+	data := ExtractIssueData(issue, client.Server, parentKey, parentSummary)
+	return &data, nil
+}
 
+// GetSubtasks fetches subtasks for a parent issue, dispatching the per-child
+// GetIssueDetails calls across concurrency workers. The returned failed
+// count includes both a failure to fetch the parent itself and any dropped
+// subtask fetch, so callers can tell a partial result from a full one.
+func GetSubtasks(client *JiraClient, parentKey, parentSummary string, concurrency int) (subtasks []IssueData, failed int) {
 	parentIssue, err := client.GetIssue(parentKey)
 	if err != nil {
 		logError("Failed to get subtasks for %s: %v", parentKey, err)
-		return subtasks
+		return nil, 1
 	}
 
 	fields := getMap(parentIssue, "fields")
@@ -220,28 +277,28 @@ func GetSubtasks(client *JiraClient, parentKey, parentSummary string) []IssueDat
 	}
 
 	subtaskRefs := getMapList(fields, "subtasks")
+	jobs := make([]issueFetchJob, 0, len(subtaskRefs))
 	for _, ref := range subtaskRefs {
-		subtaskKey := getString(ref, "key")
-		if subtaskKey != "" {
-			data, err := GetIssueDetails(client, subtaskKey, parentKey, parentSummary)
-			if err == nil && data != nil {
-				subtasks = append(subtasks, *data)
-			}
+		if subtaskKey := getString(ref, "key"); subtaskKey != "" {
+			jobs = append(jobs, issueFetchJob{IssueKey: subtaskKey, ParentKey: parentKey, ParentSummary: parentSummary})
 		}
 	}
 
+	subtasks, failed = FetchIssuesConcurrent(client, jobs, concurrency)
 	logInfo("  Found %d subtasks for %s", len(subtasks), parentKey)
-	return subtasks
+	return subtasks, failed
 }
 
-// GetLinkedIssues fetches linked issues for a parent issue
-func GetLinkedIssues(client *JiraClient, parentKey, parentSummary string) []IssueData {
-	var linked []IssueData
-
+// GetLinkedIssues fetches linked issues for a parent issue, dispatching the
+// per-link GetIssueDetails calls across concurrency workers. The returned
+// failed count includes both a failure to fetch the parent itself and any
+// dropped linked-issue fetch, so callers can tell a partial result from a
+// full one.
+func GetLinkedIssues(client *JiraClient, parentKey, parentSummary string, concurrency int) (linked []IssueData, failed int) {
 	parentIssue, err := client.GetIssue(parentKey)
 	if err != nil {
 		logError("Failed to get linked issues for %s: %v", parentKey, err)
-		return linked
+		return nil, 1
 	}
 
 	fields := getMap(parentIssue, "fields")
@@ -250,24 +307,22 @@ func GetLinkedIssues(client *JiraClient, parentKey, parentSummary string) []Issu
 	}
 
 	issueLinks := getMapList(fields, "issuelinks")
+	jobs := make([]issueFetchJob, 0, len(issueLinks))
 	for _, link := range issueLinks {
 		linkedIssue := getMap(link, "outwardIssue")
 		if linkedIssue == nil {
 			linkedIssue = getMap(link, "inwardIssue")
 		}
 		if linkedIssue != nil {
-			linkedKey := getString(linkedIssue, "key")
-			if linkedKey != "" {
-				data, err := GetIssueDetails(client, linkedKey, parentKey, parentSummary)
-				if err == nil && data != nil {
-					linked = append(linked, *data)
-				}
+			if linkedKey := getString(linkedIssue, "key"); linkedKey != "" {
+				jobs = append(jobs, issueFetchJob{IssueKey: linkedKey, ParentKey: parentKey, ParentSummary: parentSummary})
 			}
 		}
 	}
 
+	linked, failed = FetchIssuesConcurrent(client, jobs, concurrency)
 	logInfo("  Found %d linked issues for %s", len(linked), parentKey)
-	return linked
+	return linked, failed
 }
 
 // GetStatusEmoji returns the emoji for a status name
@@ -398,8 +453,10 @@ func GetStatusPriority(statusName string) int {
 	return 999
 }
 
-// RenderMarkdownReport renders issues as a markdown report
-func RenderMarkdownReport(issues []IssueData, showParent bool, since *time.Time, title string) string {
+// RenderMarkdownReport renders issues as a markdown report. When prevState is
+// non-nil, Trending is recomputed from the delta against the prior run and a
+// "Changes since <timestamp>" section is appended.
+func RenderMarkdownReport(issues []IssueData, showParent bool, since *time.Time, title string, prevState *StateStore) string {
 	var result []string
 
 	if title == "" {
@@ -415,61 +472,16 @@ func RenderMarkdownReport(issues []IssueData, showParent bool, since *time.Time,
 		result = append(result, "|---|:--|:--|:--|:--|")
 	}
 
-	// Filter issues
-	var filteredIssues []IssueData
-	for _, issue := range issues {
-		if since != nil {
-			timestamp := issue.Updated
-			if timestamp == "" || timestamp == "N/A" {
-				continue
-			}
-			updateDate, err := ParseJiraDate(timestamp)
-			if err != nil {
-				logWarning("Could not parse date '%s': %v", timestamp, err)
-				continue
-			}
-			if updateDate.Before(*since) {
-				continue
-			}
-		}
-		filteredIssues = append(filteredIssues, issue)
-	}
-
-	// Sort issues
-	sort.Slice(filteredIssues, func(i, j int) bool {
-		// By status priority
-		pi := GetStatusPriority(filteredIssues[i].StatusName)
-		pj := GetStatusPriority(filteredIssues[j].StatusName)
-		if pi != pj {
-			return pi < pj
-		}
-
-		// By target end
-		ti := filteredIssues[i].TargetEnd
-		tj := filteredIssues[j].TargetEnd
-		if ti == "" {
-			ti = "9999-99-99"
-		}
-		if tj == "" {
-			tj = "9999-99-99"
-		}
-		if ti != tj {
-			return ti < tj
-		}
-
-		// By updated
-		ui := filteredIssues[i].Updated
-		uj := filteredIssues[j].Updated
-		if ui != uj {
-			return ui < uj
-		}
-
-		// By summary
-		return filteredIssues[i].Summary < filteredIssues[j].Summary
-	})
+	filteredIssues := filterAndSortIssues(issues, since)
 
 	// Render rows
-	for _, issue := range filteredIssues {
+	for i, issue := range filteredIssues {
+		if prevState != nil && issue.Trending != "overdue" {
+			if prevIssue, existed := prevState.Issues[issue.Key]; existed {
+				issue.Trending = TrendingFromDelta(prevIssue, issue)
+				filteredIssues[i] = issue
+			}
+		}
 		issueLink := fmt.Sprintf("[%s](%s)", issue.Summary, issue.URL)
 		statusWithEmoji := fmt.Sprintf("%s %s", issue.Emoji, issue.Trending)
 		targetEnd := FormatDate(issue.TargetEnd)
@@ -488,19 +500,116 @@ func RenderMarkdownReport(issues []IssueData, showParent bool, since *time.Time,
 	}
 
 	result = append(result, "\n")
+
+	if prevState != nil {
+		changes := ComputeChanges(prevState, filteredIssues)
+		if section := RenderChangesSection(changes, prevState.GeneratedAt); section != "" {
+			result = append(result, section)
+		}
+	}
+
 	return strings.Join(result, "\n")
 }
 
-// GenerateReport generates a report of issues
+// isTargetDateField reports whether a changelog field name refers to one of
+// the resolved custom fields (by its display name, as Jira's changelog
+// reports it), so RenderChangelogSection can call out target-date shifts.
+func isTargetDateField(field string) bool {
+	_, ok := customFields[field]
+	return ok
+}
+
+// RenderChangelogSection renders a "What changed since <date>" digest from
+// each issue's changelog entries that fall on or after since, grouped by
+// issue. Unlike RenderChangesSection (which diffs against a prior
+// StateStore snapshot), this reads Jira's own changelog, so it works even on
+// a first run and reflects every transition in the window, not just the
+// delta between two runs. It returns "" when nothing changed in the window.
+func RenderChangelogSection(issues []IssueData, since time.Time) string {
+	var lines []string
+	for _, issue := range issues {
+		var entries []string
+		for _, entry := range issue.Changelog {
+			created, err := ParseJiraDate(entry.Created)
+			if err != nil || created.Before(since) {
+				continue
+			}
+			switch {
+			case entry.Field == "status":
+				entries = append(entries, fmt.Sprintf("  - %s → %s (%s)", entry.FromString, entry.ToString, entry.Author))
+			case entry.Field == "assignee":
+				entries = append(entries, fmt.Sprintf("  - reassigned from %s to %s (%s)", entry.FromString, entry.ToString, entry.Author))
+			case isTargetDateField(entry.Field):
+				entries = append(entries, fmt.Sprintf("  - target date: %s → %s (%s)", entry.FromString, entry.ToString, entry.Author))
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- [%s](%s): %s", issue.Key, issue.URL, issue.Summary))
+		lines = append(lines, entries...)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n### What changed since %s\n%s", since.Format("2006-01-02"), joinLines(lines))
+}
+
+// augmentWithChangelog fetches each issue's changelog and attaches it to
+// issues in place. It's only worth the extra round trip when --since is set
+// and RenderChangelogSection will actually read it. The returned failed
+// count lets GenerateReport know some issues' changelogs are incomplete.
+func augmentWithChangelog(client *JiraClient, issues []IssueData, concurrency int) (failed int) {
+	jobs := make([]issueFetchJob, len(issues))
+	for i, issue := range issues {
+		jobs[i] = issueFetchJob{IssueKey: issue.Key, WithChangelog: true}
+	}
+	fetched, failed := FetchIssuesConcurrent(client, jobs, concurrency)
+
+	byKey := make(map[string][]ChangelogEntry, len(fetched))
+	for _, issue := range fetched {
+		byKey[issue.Key] = issue.Changelog
+	}
+	for i := range issues {
+		issues[i].Changelog = byKey[issues[i].Key]
+	}
+	return failed
+}
+
+// GenerateReport generates a report of issues, fetching issue details across
+// concurrency workers. When stateFile is set and its cached Watermark still
+// matches this run's query, a JQL run only re-fetches issues updated since
+// that watermark and carries anything else over from the cached state; a run
+// over explicit issue keys still fetches every key.
 func GenerateReport(client *JiraClient, issueKeys []string, showParent, showSubtasks, showLinked bool,
-	since *time.Time, outputFile, jqlQuery string) {
+	since *time.Time, outputFile, jqlQuery, stateFile string, concurrency int, format OutputFormat, slackWebhook string) {
+
+	var prevState *StateStore
+	if stateFile != "" {
+		var err error
+		prevState, err = LoadStateStore(stateFile)
+		if err != nil {
+			logWarning("Could not load state file %s: %v", stateFile, err)
+		}
+	}
+
+	queryHash := QueryHash(client.Server, jqlQuery, issueKeys)
+	incremental := prevState != nil && prevState.QueryHash == queryHash && prevState.Watermark != ""
+
+	runJQL := jqlQuery
+	if incremental && jqlQuery != "" {
+		runJQL = fmt.Sprintf(`(%s) AND updated >= "%s"`, jqlQuery, prevState.Watermark)
+		logInfo("Incremental run: only fetching issues updated since %s", prevState.Watermark)
+	}
 
 	var rootIssues []IssueData
 	var childIssues []IssueData
+	var totalFailed int
 
 	if jqlQuery != "" {
-		logInfo("Executing JQL query: %s", jqlQuery)
-		issues, err := client.SearchIssues(jqlQuery, 1000)
+		logInfo("Executing JQL query: %s", runJQL)
+		issues, err := client.SearchIssues(runJQL, 1000)
 		if err != nil {
 			logError("JQL query failed: %v", err)
 			return
@@ -515,13 +624,15 @@ func GenerateReport(client *JiraClient, issueKeys []string, showParent, showSubt
 				parentSummary := issueData.Summary
 
 				if showSubtasks {
-					subtasks := GetSubtasks(client, issueKey, parentSummary)
+					subtasks, failed := GetSubtasks(client, issueKey, parentSummary, concurrency)
 					childIssues = append(childIssues, subtasks...)
+					totalFailed += failed
 				}
 
 				if showLinked {
-					linked := GetLinkedIssues(client, issueKey, parentSummary)
+					linked, failed := GetLinkedIssues(client, issueKey, parentSummary, concurrency)
 					childIssues = append(childIssues, linked...)
+					totalFailed += failed
 				}
 			}
 		}
@@ -532,25 +643,25 @@ func GenerateReport(client *JiraClient, issueKeys []string, showParent, showSubt
 		}
 		logInfo("Found %d issues from JQL query", len(issueKeys))
 	} else {
-		for _, issueKey := range issueKeys {
-			logInfo("Processing %s...", issueKey)
-			data, err := GetIssueDetails(client, issueKey, "", "")
-			if err != nil {
-				continue
-			}
-			if data != nil {
-				rootIssues = append(rootIssues, *data)
-				parentSummary := data.Summary
+		jobs := make([]issueFetchJob, len(issueKeys))
+		for i, issueKey := range issueKeys {
+			jobs[i] = issueFetchJob{IssueKey: issueKey}
+		}
+		rootIssues, totalFailed = FetchIssuesConcurrent(client, jobs, concurrency)
 
-				if showSubtasks {
-					subtasks := GetSubtasks(client, issueKey, parentSummary)
-					childIssues = append(childIssues, subtasks...)
-				}
+		for _, data := range rootIssues {
+			parentSummary := data.Summary
 
-				if showLinked {
-					linked := GetLinkedIssues(client, issueKey, parentSummary)
-					childIssues = append(childIssues, linked...)
-				}
+			if showSubtasks {
+				subtasks, failed := GetSubtasks(client, data.Key, parentSummary, concurrency)
+				childIssues = append(childIssues, subtasks...)
+				totalFailed += failed
+			}
+
+			if showLinked {
+				linked, failed := GetLinkedIssues(client, data.Key, parentSummary, concurrency)
+				childIssues = append(childIssues, linked...)
+				totalFailed += failed
 			}
 		}
 	}
@@ -564,30 +675,72 @@ func GenerateReport(client *JiraClient, issueKeys []string, showParent, showSubt
 		customTitle = fmt.Sprintf("[%s: %s](%s)", parentKey, parentSummary, parentURL)
 	}
 
-	var markdownReport string
+	renderedIssues := rootIssues
 	if showSubtasks || showLinked {
-		markdownReport = RenderMarkdownReport(childIssues, showParent, since, customTitle)
-	} else {
-		markdownReport = RenderMarkdownReport(rootIssues, false, since, customTitle)
+		renderedIssues = childIssues
+	}
+	if incremental {
+		renderedIssues = MergeIncremental(prevState.Issues, renderedIssues)
+	}
+
+	if since != nil {
+		totalFailed += augmentWithChangelog(client, renderedIssues, concurrency)
+	}
+
+	opts := RenderOptions{ShowParent: showParent && (showSubtasks || showLinked), Since: since, Title: customTitle, PrevState: prevState}
+	renderer, err := GetRenderer(format)
+	if err != nil {
+		logError("%v", err)
+		return
+	}
+	report, err := renderer.Render(renderedIssues, opts)
+	if err != nil {
+		logError("Rendering %s report: %v", format, err)
+		return
+	}
+
+	if stateFile != "" && totalFailed > 0 {
+		logWarning("%d issue fetch(es) failed; leaving state file %s untouched so the next run retries them", totalFailed, stateFile)
+	} else if stateFile != "" {
+		watermark := MaxUpdated(renderedIssues)
+		if incremental && watermark == "" {
+			watermark = prevState.Watermark // nothing changed; don't regress the watermark
+		}
+		if err := SaveStateStore(stateFile, client.Server, queryHash, watermark, renderedIssues); err != nil {
+			logWarning("Could not save state file %s: %v", stateFile, err)
+		}
+	}
+
+	if format == OutputFormatSlack && slackWebhook != "" {
+		if err := PostSlackBlocks(slackWebhook, report); err != nil {
+			logError("Posting to Slack: %v", err)
+		}
+		return
 	}
 
 	// Output
 	if outputFile != "" {
-		f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		existing, err := os.ReadFile(outputFile)
+		if err != nil && !os.IsNotExist(err) {
+			logError("Error reading file %s: %v", outputFile, err)
+			fmt.Println(string(report))
+			return
+		}
+
+		combined, err := renderer.Combine(existing, report)
 		if err != nil {
-			logError("Error opening file %s: %v", outputFile, err)
-			fmt.Println(markdownReport)
+			logError("Writing %s: %v", outputFile, err)
+			fmt.Println(string(report))
 			return
 		}
-		defer f.Close()
 
-		fi, _ := f.Stat()
-		if fi.Size() > 0 {
-			f.WriteString("\n\n\n\n")
+		if err := os.WriteFile(outputFile, combined, 0644); err != nil {
+			logError("Error writing file %s: %v", outputFile, err)
+			fmt.Println(string(report))
+			return
 		}
-		f.WriteString(markdownReport)
 	} else {
-		fmt.Println(markdownReport)
+		fmt.Println(string(report))
 	}
 }
 
@@ -600,6 +753,9 @@ func main() {
 	sinceStr := flag.String("since", "", "Only include issues updated on or after this date (YYYY-MM-DD)")
 	outputFile := flag.String("output-file", "", "Write/append the markdown report to this file")
 	outputFileShort := flag.String("o", "", "Write/append the markdown report to this file (short)")
+	stateFile := flag.String("state-file", "", "Persist issue state here and report what changed since the last run (default: $XDG_STATE_HOME/jira-report/state.json)")
+	resetState := flag.Bool("reset-state", false, "Discard any cached state and perform a full, non-incremental run")
+	noState := flag.Bool("no-state", false, "Disable state persistence and incremental fetching entirely")
 	individual := flag.Bool("individual", false, "Generate a separate report section for each issue")
 	individualShort := flag.Bool("i", false, "Generate a separate report section for each issue (short)")
 	useStdin := flag.Bool("stdin", false, "Read issue keys from stdin (one per line)")
@@ -608,6 +764,12 @@ func main() {
 	verboseShort := flag.Bool("v", false, "Enable verbose debug logging (short)")
 	quiet := flag.Bool("quiet", false, "Suppress non-essential output")
 	quietShort := flag.Bool("q", false, "Suppress non-essential output (short)")
+	logFormat := flag.String("log-format", "text", "Log output format: text|json")
+	logLevelFlag := flag.String("log-level", "", "Log level: error|warn|info|debug (overrides --verbose/--quiet)")
+	noCacheFlag := flag.Bool("no-cache", false, "Bypass the on-disk response cache, even if JIRA_CACHE_DIR is set")
+	concurrency := flag.Int("concurrency", defaultFetchConcurrency, "Number of issues to fetch concurrently")
+	formatFlag := flag.String("format", "md", "Output format: md|html|json|csv|slack")
+	slackWebhook := flag.String("slack-webhook", "", "POST the rendered report to this Slack incoming webhook URL (requires --format slack)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage: jira-report [options] <issue_keys...>
@@ -622,6 +784,8 @@ Environment variables:
   JIRA_SERVER     - Jira server URL (required)
   JIRA_API_TOKEN  - API token or Personal Access Token (required)
   JIRA_EMAIL      - Your email/username (required for Cloud, optional for Server)
+  JIRA_CACHE_DIR  - Cache GET responses (search/GetIssue/field lookups) under this directory
+  JIRA_AUTH_MODE  - basic|pat|oauth1|oauth2 (default: auto-detected from JIRA_SERVER)
 
 For Jira Cloud (*.atlassian.net):
   export JIRA_SERVER="https://mycompany.atlassian.net"
@@ -632,14 +796,25 @@ For Jira Server/Data Center:
   export JIRA_SERVER="https://jira.company.com"
   export JIRA_API_TOKEN="<Personal Access Token from Jira profile>"
 
+For OAuth 1.0a (Jira Server/Data Center, JIRA_AUTH_MODE=oauth1):
+  export JIRA_OAUTH_CONSUMER_KEY="<consumer key registered with the app link>"
+  export JIRA_OAUTH_PRIVATE_KEY_FILE="/path/to/private_key.pem"
+
+For OAuth 2.0 3LO (Jira Cloud, JIRA_AUTH_MODE=oauth2):
+  export JIRA_OAUTH_CLIENT_ID="<client ID from developer.atlassian.com>"
+  export JIRA_OAUTH_CLIENT_SECRET="<client secret>"
+  export JIRA_OAUTH_REDIRECT_URL="http://localhost:8976/callback" # optional, must match the app's registered redirect URL
+
 Examples:
   jira-report PROJECT-123 PROJECT-456
   jira-report --jql "project = MYPROJ AND status != Done"
   jira-report --include-subtasks --since 2025-01-01 PROJECT-123
+  jira-report --format slack --slack-webhook "$SLACK_WEBHOOK_URL" PROJECT-123
 `)
 	}
 
 	flag.Parse()
+	noCache = *noCacheFlag
 
 	// Merge short flags
 	if *outputFileShort != "" && *outputFile == "" {
@@ -658,13 +833,28 @@ Examples:
 		*quiet = true
 	}
 
-	// Set log level
-	if *verbose {
-		logLevel = LogLevelDebug
-	} else if *quiet {
-		logLevel = LogLevelError
-	} else {
-		logLevel = LogLevelWarning
+	// Set log level and format
+	format, err := ParseFormat(*logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	setLogFormat(format)
+
+	switch {
+	case *logLevelFlag != "":
+		level, err := ParseLevel(*logLevelFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		setLogLevel(level)
+	case *verbose:
+		setLogLevel(LevelDebug)
+	case *quiet:
+		setLogLevel(LevelError)
+	default:
+		setLogLevel(LevelWarning)
 	}
 
 	// Collect issue keys
@@ -691,6 +881,17 @@ Examples:
 
 	logInfo("Processing %d issues...", len(issueKeys))
 
+	// Parse output format
+	outputFormat, err := ParseOutputFormat(*formatFlag)
+	if err != nil {
+		logError("%v", err)
+		os.Exit(1)
+	}
+	if *slackWebhook != "" && outputFormat != OutputFormatSlack {
+		logError("--slack-webhook requires --format slack")
+		os.Exit(1)
+	}
+
 	// Parse since date
 	var since *time.Time
 	if *sinceStr != "" {
@@ -704,6 +905,26 @@ Examples:
 		logInfo("Filtering issues updated after %s", since)
 	}
 
+	// Resolve the state file: --no-state disables it outright, otherwise fall
+	// back to the XDG default when --state-file wasn't given, and
+	// --reset-state discards whatever's cached there before this run reads it.
+	effectiveStateFile := *stateFile
+	if *noState {
+		effectiveStateFile = ""
+	} else if effectiveStateFile == "" {
+		path, err := DefaultStateFilePath()
+		if err != nil {
+			logWarning("Could not determine default state file location: %v", err)
+		} else {
+			effectiveStateFile = path
+		}
+	}
+	if *resetState && effectiveStateFile != "" {
+		if err := os.Remove(effectiveStateFile); err != nil && !os.IsNotExist(err) {
+			logWarning("Could not reset state file %s: %v", effectiveStateFile, err)
+		}
+	}
+
 	// Remove existing output file
 	if *outputFile != "" {
 		if _, err := os.Stat(*outputFile); err == nil {
@@ -731,7 +952,11 @@ Examples:
 				*includeLinked,
 				since,
 				*outputFile,
-				"")
+				"",
+				effectiveStateFile,
+				*concurrency,
+				outputFormat,
+				*slackWebhook)
 		}
 	} else {
 		GenerateReport(client, issueKeys,
@@ -740,6 +965,10 @@ Examples:
 			*includeLinked,
 			since,
 			*outputFile,
-			*jqlQuery)
+			*jqlQuery,
+			effectiveStateFile,
+			*concurrency,
+			outputFormat,
+			*slackWebhook)
 	}
 }
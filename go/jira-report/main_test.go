@@ -171,7 +171,7 @@ func TestRenderMarkdownReport(t *testing.T) {
 			Trending:   "done",
 		},
 	}
-	out := RenderMarkdownReport(issues, false, nil, "Test Report")
+	out := RenderMarkdownReport(issues, false, nil, "Test Report", nil)
 	if out == "" {
 		t.Error("RenderMarkdownReport returned empty string")
 	}
@@ -193,7 +193,7 @@ func TestRenderMarkdownReport_filterSince(t *testing.T) {
 		{Key: "X-1", Updated: "2024-12-01T00:00:00Z", Summary: "Old"},
 		{Key: "X-2", Updated: "2025-02-01T00:00:00Z", Summary: "New"},
 	}
-	out := RenderMarkdownReport(issues, false, &jan1, "")
+	out := RenderMarkdownReport(issues, false, &jan1, "", nil)
 	if strings.Contains(out, "Old") {
 		t.Error("expected issue updated before since to be filtered out")
 	}
@@ -201,3 +201,44 @@ func TestRenderMarkdownReport_filterSince(t *testing.T) {
 		t.Error("expected issue updated after since to be included")
 	}
 }
+
+func TestRenderMarkdownReport_changesSection(t *testing.T) {
+	prev := &StateStore{
+		Magic:         stateMagic,
+		SchemaVersion: stateSchemaVersion,
+		GeneratedAt:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Issues: map[string]IssueData{
+			"A-1": {Key: "A-1", Summary: "First", StatusName: "in progress", Assignee: "Alice"},
+		},
+	}
+	issues := []IssueData{
+		{Key: "A-1", URL: "https://jira/a", Summary: "First", StatusName: "done", Assignee: "Bob", Emoji: "🟣", Trending: "done"},
+		{Key: "B-1", URL: "https://jira/b", Summary: "Second", StatusName: "new", Emoji: "⚪", Trending: "not started"},
+	}
+
+	out := RenderMarkdownReport(issues, false, nil, "", prev)
+	if !strings.Contains(out, "Changes since 2025-01-01") {
+		t.Errorf("expected a changes section: %s", out)
+	}
+	if !strings.Contains(out, "in progress → done") {
+		t.Errorf("expected status transition for A-1: %s", out)
+	}
+	if !strings.Contains(out, "reassigned from Alice to Bob") {
+		t.Errorf("expected assignee change for A-1: %s", out)
+	}
+	if !strings.Contains(out, "new issue \"Second\"") {
+		t.Errorf("expected B-1 to be reported as new: %s", out)
+	}
+}
+
+func TestComputeChanges_removedIssue(t *testing.T) {
+	prev := &StateStore{
+		Issues: map[string]IssueData{
+			"A-1": {Key: "A-1", Summary: "First", URL: "https://jira/a"},
+		},
+	}
+	changes := ComputeChanges(prev, nil)
+	if len(changes) != 1 || changes[0].Kind != "removed" || changes[0].Key != "A-1" {
+		t.Errorf("expected A-1 to be reported removed, got %+v", changes)
+	}
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OAuth1Credentials holds everything needed to sign a request with RSA-SHA1
+// three-legged OAuth 1.0a, as used by Jira Server/Data Center.
+type OAuth1Credentials struct {
+	ConsumerKey string
+	PrivateKey  *rsa.PrivateKey
+	Token       string
+	TokenSecret string
+}
+
+// oauth1TokenCache is the JSON shape persisted under
+// $XDG_CONFIG_HOME/jiraclient/ so a completed handshake doesn't have to be
+// repeated on every run.
+type oauth1TokenCache struct {
+	Server      string `json:"server"`
+	ConsumerKey string `json:"consumer_key"`
+	Token       string `json:"token"`
+	TokenSecret string `json:"token_secret"`
+}
+
+// NewJiraClientOAuth creates a Jira client authenticated with OAuth 1.0a,
+// performing the three-legged handshake (request token -> user authorization
+// -> access token exchange) the first time it runs against a given server,
+// and reusing the cached access token afterwards.
+func NewJiraClientOAuth(server, consumerKey, privateKeyPath string) (*JiraClient, error) {
+	server = strings.TrimRight(server, "/")
+
+	keyData, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("oauth1: reading private key: %w", err)
+	}
+	privateKey, err := parseRSAPrivateKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("oauth1: parsing private key: %w", err)
+	}
+
+	creds := &OAuth1Credentials{ConsumerKey: consumerKey, PrivateKey: privateKey}
+
+	if cached, err := loadOAuth1TokenCache(server, consumerKey); err == nil {
+		creds.Token = cached.Token
+		creds.TokenSecret = cached.TokenSecret
+		logDebug("oauth1: reusing cached access token for %s", server)
+	} else {
+		if err := performOAuth1Handshake(server, creds); err != nil {
+			return nil, fmt.Errorf("oauth1: handshake: %w", err)
+		}
+		if err := saveOAuth1TokenCache(server, creds); err != nil {
+			logWarning("oauth1: could not cache access token: %v", err)
+		}
+	}
+
+	return &JiraClient{
+		Server:     server,
+		APIVersion: "2",
+		AuthMode:   AuthOAuth1,
+		OAuth1:     creds,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		RateLimit:  NewRateLimiter(defaultRateLimit),
+		CacheTTL:   defaultCacheTTL,
+	}, nil
+}
+
+// performOAuth1Handshake runs the interactive request-token -> authorize ->
+// access-token exchange and fills in creds.Token/TokenSecret.
+func performOAuth1Handshake(server string, creds *OAuth1Credentials) error {
+	requestToken, requestSecret, err := oauth1RequestToken(server, creds)
+	if err != nil {
+		return fmt.Errorf("requesting token: %w", err)
+	}
+
+	fmt.Printf("Open the following URL in a browser and authorize access:\n\n  %s/plugins/servlet/oauth/authorize?oauth_token=%s\n\n",
+		server, url.QueryEscape(requestToken))
+	fmt.Print("Enter the verification code shown after authorizing: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	verifier, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading verifier: %w", err)
+	}
+	verifier = strings.TrimSpace(verifier)
+
+	token, tokenSecret, err := oauth1AccessToken(server, creds, requestToken, requestSecret, verifier)
+	if err != nil {
+		return fmt.Errorf("exchanging access token: %w", err)
+	}
+
+	creds.Token = token
+	creds.TokenSecret = tokenSecret
+	return nil
+}
+
+// oauth1RequestToken performs leg one of the handshake.
+func oauth1RequestToken(server string, creds *OAuth1Credentials) (token, secret string, err error) {
+	endpoint := server + "/plugins/servlet/oauth/request-token"
+	header, err := signOAuth1Request("POST", endpoint, &OAuth1Credentials{
+		ConsumerKey: creds.ConsumerKey,
+		PrivateKey:  creds.PrivateKey,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return oauth1PostForToken(endpoint, header)
+}
+
+// oauth1AccessToken performs leg three of the handshake.
+func oauth1AccessToken(server string, creds *OAuth1Credentials, requestToken, requestSecret, verifier string) (token, secret string, err error) {
+	endpoint := server + "/plugins/servlet/oauth/access-token"
+	header, err := signOAuth1RequestWithVerifier("POST", endpoint, creds, requestToken, verifier)
+	if err != nil {
+		return "", "", err
+	}
+	return oauth1PostForToken(endpoint, header)
+}
+
+func oauth1PostForToken(endpoint, authHeader string) (token, secret string, err error) {
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", authHeader)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if resp.StatusCode >= 400 {
+		return "", "", fmt.Errorf("server returned %d: %s", resp.StatusCode, truncate(string(body), 500))
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", "", err
+	}
+	token = values.Get("oauth_token")
+	secret = values.Get("oauth_token_secret")
+	if token == "" {
+		return "", "", fmt.Errorf("response did not contain an oauth_token: %s", truncate(string(body), 200))
+	}
+	return token, secret, nil
+}
+
+// signOAuth1Request builds the "Authorization: OAuth ..." header for a
+// request signed with RSA-SHA1, per creds (which may or may not yet have an
+// access token - request-token and API calls both go through this).
+func signOAuth1Request(method, rawURL string, creds *OAuth1Credentials) (string, error) {
+	return signOAuth1RequestWithVerifier(method, rawURL, creds, creds.Token, "")
+}
+
+// signOAuth1RequestWithVerifier is the common signing path; verifier is only
+// non-empty during the access-token exchange, and token overrides
+// creds.Token so the same helper covers all three legs.
+func signOAuth1RequestWithVerifier(method, rawURL string, creds *OAuth1Credentials, token, verifier string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := oauth1Nonce()
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]string{
+		"oauth_consumer_key":     creds.ConsumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "RSA-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+	if token != "" {
+		params["oauth_token"] = token
+	}
+	if verifier != "" {
+		params["oauth_verifier"] = verifier
+	} else if token == "" {
+		// leg one (request-token): out-of-band callback, no verifier yet.
+		params["oauth_callback"] = "oob"
+	}
+
+	// Per RFC 5849 3.4.1.3, the signature base string covers every request
+	// parameter, not just the oauth_* ones - merge the URL's query string in
+	// for signing, but keep it out of the Authorization header itself.
+	signingParams := make(map[string]string, len(params)+len(parsed.Query()))
+	for k, v := range params {
+		signingParams[k] = v
+	}
+	for k, values := range parsed.Query() {
+		if len(values) > 0 {
+			signingParams[k] = values[0]
+		}
+	}
+
+	signature, err := signOAuth1Base(method, parsed, signingParams, creds.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	params["oauth_signature"] = signature
+
+	var b strings.Builder
+	b.WriteString("OAuth ")
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, `%s="%s"`, oauth1Encode(k), oauth1Encode(params[k]))
+	}
+	return b.String(), nil
+}
+
+// signOAuth1Base builds the OAuth 1.0a signature base string and signs it
+// with RSA-SHA1 using the consumer's private key.
+func signOAuth1Base(method string, u *url.URL, params map[string]string, privateKey *rsa.PrivateKey) (string, error) {
+	baseURL := fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path)
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = oauth1Encode(k) + "=" + oauth1Encode(params[k])
+	}
+
+	base := strings.Join([]string{
+		method,
+		oauth1Encode(baseURL),
+		oauth1Encode(strings.Join(pairs, "&")),
+	}, "&")
+
+	hashed := sha1.Sum([]byte(base))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA1, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func oauth1Encode(s string) string {
+	return url.QueryEscape(s)
+}
+
+func oauth1Nonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func parseRSAPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+// oauth1ConfigDir returns $XDG_CONFIG_HOME/jiraclient, falling back to
+// ~/.config/jiraclient.
+func oauth1ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "jiraclient"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "jiraclient"), nil
+}
+
+func oauth1CacheFile(server, consumerKey string) (string, error) {
+	dir, err := oauth1ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	safeServer := strings.NewReplacer("://", "_", "/", "_", ":", "_").Replace(server)
+	return filepath.Join(dir, fmt.Sprintf("oauth1-%s-%s.json", safeServer, consumerKey)), nil
+}
+
+func loadOAuth1TokenCache(server, consumerKey string) (*oauth1TokenCache, error) {
+	path, err := oauth1CacheFile(server, consumerKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache oauth1TokenCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+func saveOAuth1TokenCache(server string, creds *OAuth1Credentials) error {
+	path, err := oauth1CacheFile(server, creds.ConsumerKey)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	cache := oauth1TokenCache{
+		Server:      server,
+		ConsumerKey: creds.ConsumerKey,
+		Token:       creds.Token,
+		TokenSecret: creds.TokenSecret,
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+	return key
+}
+
+func TestSignOAuth1RequestIncludesRequiredParams(t *testing.T) {
+	creds := &OAuth1Credentials{ConsumerKey: "consumer-123", PrivateKey: testRSAKey(t), Token: "tok"}
+
+	header, err := signOAuth1Request("GET", "https://jira.example.com/rest/api/2/myself", creds)
+	if err != nil {
+		t.Fatalf("signOAuth1Request: %v", err)
+	}
+
+	for _, want := range []string{"OAuth ", "oauth_consumer_key=", "oauth_signature_method=\"RSA-SHA1\"", "oauth_token=", "oauth_signature="} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header %q missing %q", header, want)
+		}
+	}
+}
+
+func TestSignOAuth1RequestLegOneUsesOOBCallback(t *testing.T) {
+	creds := &OAuth1Credentials{ConsumerKey: "consumer-123", PrivateKey: testRSAKey(t)}
+
+	header, err := signOAuth1Request("POST", "https://jira.example.com/plugins/servlet/oauth/request-token", creds)
+	if err != nil {
+		t.Fatalf("signOAuth1Request: %v", err)
+	}
+	if !strings.Contains(header, "oauth_callback=\""+url.QueryEscape("oob")+"\"") {
+		t.Errorf("expected leg-one header to request an out-of-band callback, got %q", header)
+	}
+}
+
+// parseOAuthHeader decodes an "OAuth k1="v1", k2="v2""-style Authorization
+// header back into a plain key/value map for assertions.
+func parseOAuthHeader(header string) map[string]string {
+	header = strings.TrimPrefix(header, "OAuth ")
+	fields := make(map[string]string)
+	for _, part := range strings.Split(header, ", ") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, _ := url.QueryUnescape(kv[0])
+		val, _ := url.QueryUnescape(strings.Trim(kv[1], `"`))
+		fields[key] = val
+	}
+	return fields
+}
+
+func TestSignOAuth1RequestIncludesQueryParamsInSignature(t *testing.T) {
+	creds := &OAuth1Credentials{ConsumerKey: "consumer-123", PrivateKey: testRSAKey(t), Token: "tok"}
+	rawURL := "https://jira.example.com/rest/api/2/search?jql=" + url.QueryEscape("project = FOO") + "&maxResults=50"
+
+	header, err := signOAuth1Request("GET", rawURL, creds)
+	if err != nil {
+		t.Fatalf("signOAuth1Request: %v", err)
+	}
+
+	fields := parseOAuthHeader(header)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	expectedParams := map[string]string{
+		"oauth_consumer_key":     fields["oauth_consumer_key"],
+		"oauth_nonce":            fields["oauth_nonce"],
+		"oauth_signature_method": fields["oauth_signature_method"],
+		"oauth_timestamp":        fields["oauth_timestamp"],
+		"oauth_version":          fields["oauth_version"],
+		"oauth_token":            fields["oauth_token"],
+	}
+	for k, values := range u.Query() {
+		expectedParams[k] = values[0]
+	}
+
+	want, err := signOAuth1Base("GET", u, expectedParams, creds.PrivateKey)
+	if err != nil {
+		t.Fatalf("signOAuth1Base: %v", err)
+	}
+	if fields["oauth_signature"] != want {
+		t.Errorf("oauth_signature = %q, want %q (query params must be part of the signature base string, per RFC 5849 3.4.1.3)", fields["oauth_signature"], want)
+	}
+}
+
+func TestOAuth1TokenCacheRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	creds := &OAuth1Credentials{ConsumerKey: "consumer-123", Token: "access-tok", TokenSecret: "access-secret"}
+	if err := saveOAuth1TokenCache("https://jira.example.com", creds); err != nil {
+		t.Fatalf("saveOAuth1TokenCache: %v", err)
+	}
+
+	cached, err := loadOAuth1TokenCache("https://jira.example.com", "consumer-123")
+	if err != nil {
+		t.Fatalf("loadOAuth1TokenCache: %v", err)
+	}
+	if cached.Token != creds.Token || cached.TokenSecret != creds.TokenSecret {
+		t.Errorf("cached = %+v, want token/secret from %+v", cached, creds)
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// atlassianAuthURL and atlassianTokenURL are Atlassian's fixed 3LO endpoints;
+// unlike OAuth 1.0a (which talks to the Jira server itself), the
+// authorization-code+PKCE dance always goes through auth.atlassian.com.
+const (
+	atlassianAuthURL      = "https://auth.atlassian.com/authorize"
+	atlassianTokenURL     = "https://auth.atlassian.com/oauth/token"
+	atlassianResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	defaultOAuth2Scopes   = "read:jira-work offline_access"
+)
+
+// OAuth2Credentials holds an Atlassian 3LO access/refresh token pair plus the
+// cloud ID the token was issued for, which api.atlassian.com's proxy
+// requires in place of the server's own hostname.
+type OAuth2Credentials struct {
+	ClientID     string
+	ClientSecret string
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	CloudID      string
+}
+
+// oauth2TokenCache is the JSON shape persisted under
+// $XDG_CONFIG_HOME/jiraclient/ so a completed authorization doesn't have to
+// be repeated on every run; ExpiresAt lets GetJiraClient skip a needless
+// refresh round trip when the cached access token is still valid.
+type oauth2TokenCache struct {
+	Server       string    `json:"server"`
+	ClientID     string    `json:"client_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CloudID      string    `json:"cloud_id"`
+}
+
+// NewJiraClientOAuth2 creates a Jira client authenticated with Atlassian's
+// 3LO authorization-code+PKCE flow, reusing a cached, auto-refreshed access
+// token when one is available and running the interactive authorization
+// dance the first time it runs for a given server/client ID.
+func NewJiraClientOAuth2(server, clientID, clientSecret, redirectURL string) (*JiraClient, error) {
+	server = strings.TrimRight(server, "/")
+
+	creds := &OAuth2Credentials{ClientID: clientID, ClientSecret: clientSecret}
+
+	cached, err := loadOAuth2TokenCache(server, clientID)
+	switch {
+	case err == nil:
+		creds.AccessToken = cached.AccessToken
+		creds.RefreshToken = cached.RefreshToken
+		creds.ExpiresAt = cached.ExpiresAt
+		creds.CloudID = cached.CloudID
+		logDebug("oauth2: reusing cached token for %s", server)
+	default:
+		if err := performOAuth2Authorization(server, redirectURL, creds); err != nil {
+			return nil, fmt.Errorf("oauth2: authorization: %w", err)
+		}
+	}
+
+	if time.Now().After(creds.ExpiresAt.Add(-oauth2RefreshSkew)) {
+		if err := refreshOAuth2Token(creds); err != nil {
+			return nil, fmt.Errorf("oauth2: refreshing token: %w", err)
+		}
+	}
+
+	if err := saveOAuth2TokenCache(server, creds); err != nil {
+		logWarning("oauth2: could not cache token: %v", err)
+	}
+
+	return &JiraClient{
+		Server:     server,
+		APIVersion: "3",
+		IsCloud:    true,
+		AuthMode:   AuthOAuth2,
+		OAuth2:     creds,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		RateLimit:  NewRateLimiter(defaultRateLimit),
+		CacheTTL:   defaultCacheTTL,
+	}, nil
+}
+
+// oauth2RefreshSkew refreshes a token this long before it actually expires,
+// so a request doesn't race an access token's expiry.
+const oauth2RefreshSkew = 2 * time.Minute
+
+// EnsureFreshToken refreshes c.OAuth2's access token if it's at or past
+// expiry (minus oauth2RefreshSkew), and re-persists the cache afterwards.
+// doRequest calls this before every request signed with AuthOAuth2, so
+// refresh is transparent to the rest of the report code.
+func (c *JiraClient) EnsureFreshToken() error {
+	if c.OAuth2 == nil || time.Now().Before(c.OAuth2.ExpiresAt.Add(-oauth2RefreshSkew)) {
+		return nil
+	}
+	if err := refreshOAuth2Token(c.OAuth2); err != nil {
+		return err
+	}
+	if err := saveOAuth2TokenCache(c.Server, c.OAuth2); err != nil {
+		logWarning("oauth2: could not update cached token: %v", err)
+	}
+	return nil
+}
+
+// performOAuth2Authorization runs the interactive authorization-code+PKCE
+// exchange and fills in creds' access/refresh tokens and cloud ID.
+func performOAuth2Authorization(server, redirectURL string, creds *OAuth2Credentials) error {
+	verifier, challenge, err := oauth2PKCEPair()
+	if err != nil {
+		return fmt.Errorf("generating PKCE verifier: %w", err)
+	}
+	state, err := oauth2RandomString(16)
+	if err != nil {
+		return fmt.Errorf("generating state: %w", err)
+	}
+
+	authorizeURL := fmt.Sprintf(
+		"%s?audience=api.atlassian.com&client_id=%s&scope=%s&redirect_uri=%s&state=%s&response_type=code&prompt=consent&code_challenge=%s&code_challenge_method=S256",
+		atlassianAuthURL,
+		url.QueryEscape(creds.ClientID),
+		url.QueryEscape(defaultOAuth2Scopes),
+		url.QueryEscape(redirectURL),
+		url.QueryEscape(state),
+		url.QueryEscape(challenge),
+	)
+
+	fmt.Printf("Open the following URL in a browser and authorize access:\n\n  %s\n\n", authorizeURL)
+	fmt.Printf("After authorizing, you'll be redirected to %s?code=...\nPaste the full redirect URL here: ", redirectURL)
+
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading redirect URL: %w", err)
+	}
+	code, err := oauth2ExtractCode(strings.TrimSpace(line), state)
+	if err != nil {
+		return err
+	}
+
+	token, err := oauth2ExchangeToken(map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     creds.ClientID,
+		"client_secret": creds.ClientSecret,
+		"code":          code,
+		"redirect_uri":  redirectURL,
+		"code_verifier": verifier,
+	})
+	if err != nil {
+		return fmt.Errorf("exchanging authorization code: %w", err)
+	}
+
+	creds.AccessToken = token.AccessToken
+	creds.RefreshToken = token.RefreshToken
+	creds.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	cloudID, err := resolveCloudID(creds.AccessToken, server)
+	if err != nil {
+		return fmt.Errorf("resolving cloud ID: %w", err)
+	}
+	creds.CloudID = cloudID
+	return nil
+}
+
+// refreshOAuth2Token exchanges creds.RefreshToken for a new access/refresh
+// token pair. Atlassian rotates the refresh token on every use, so the
+// caller must persist the updated creds afterwards.
+func refreshOAuth2Token(creds *OAuth2Credentials) error {
+	if creds.RefreshToken == "" {
+		return fmt.Errorf("no refresh token available; re-run authorization")
+	}
+	token, err := oauth2ExchangeToken(map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     creds.ClientID,
+		"client_secret": creds.ClientSecret,
+		"refresh_token": creds.RefreshToken,
+	})
+	if err != nil {
+		return err
+	}
+	creds.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		creds.RefreshToken = token.RefreshToken
+	}
+	creds.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return nil
+}
+
+// oauth2TokenResponse is the JSON body Atlassian's token endpoint returns
+// for both the authorization_code and refresh_token grants.
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+func oauth2ExchangeToken(params map[string]string) (*oauth2TokenResponse, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, atlassianTokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, truncate(string(respBody), 500))
+	}
+
+	var token oauth2TokenResponse
+	if err := json.Unmarshal(respBody, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// resolveCloudID looks up the Atlassian cloud ID for server among the sites
+// the access token's grant makes accessible, since api.atlassian.com's proxy
+// addresses Jira sites by cloud ID rather than hostname.
+func resolveCloudID(accessToken, server string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, atlassianResourcesURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("accessible-resources returned %d: %s", resp.StatusCode, truncate(string(body), 500))
+	}
+
+	var resources []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(body, &resources); err != nil {
+		return "", err
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(server), "/")
+	for _, r := range resources {
+		if strings.TrimSuffix(strings.ToLower(r.URL), "/") == host {
+			return r.ID, nil
+		}
+	}
+	if len(resources) == 1 {
+		return resources[0].ID, nil
+	}
+	return "", fmt.Errorf("no accessible Jira site matched %s", server)
+}
+
+// oauth2PKCEPair generates an RFC 7636 code verifier and its S256 challenge.
+func oauth2PKCEPair() (verifier, challenge string, err error) {
+	verifier, err = oauth2RandomString(64)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+	return verifier, challenge, nil
+}
+
+func oauth2RandomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// oauth2ExtractCode pulls the "code" query parameter out of either a full
+// redirect URL or a bare code pasted directly. For a full redirect URL, its
+// "state" parameter must match expectedState (the value performOAuth2Authorization
+// generated and embedded in the authorize URL) or the callback is rejected,
+// since that comparison is the CSRF protection state exists to provide. A
+// bare pasted code carries no state to check.
+func oauth2ExtractCode(input, expectedState string) (string, error) {
+	if !strings.Contains(input, "://") {
+		return input, nil
+	}
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("parsing redirect URL: %w", err)
+	}
+	query := parsed.Query()
+	if state := query.Get("state"); state != expectedState {
+		return "", fmt.Errorf("redirect URL state %q did not match expected %q", state, expectedState)
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("redirect URL did not contain a code parameter: %s", input)
+	}
+	return code, nil
+}
+
+func oauth2CacheFile(server, clientID string) (string, error) {
+	dir, err := oauth1ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	safeServer := strings.NewReplacer("://", "_", "/", "_", ":", "_").Replace(server)
+	return filepath.Join(dir, fmt.Sprintf("oauth2-%s-%s.json", safeServer, clientID)), nil
+}
+
+func loadOAuth2TokenCache(server, clientID string) (*oauth2TokenCache, error) {
+	path, err := oauth2CacheFile(server, clientID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache oauth2TokenCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+func saveOAuth2TokenCache(server string, creds *OAuth2Credentials) error {
+	path, err := oauth2CacheFile(server, creds.ClientID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	cache := oauth2TokenCache{
+		Server:       server,
+		ClientID:     creds.ClientID,
+		AccessToken:  creds.AccessToken,
+		RefreshToken: creds.RefreshToken,
+		ExpiresAt:    creds.ExpiresAt,
+		CloudID:      creds.CloudID,
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
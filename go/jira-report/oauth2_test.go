@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestOAuth2PKCEPairMatchesS256(t *testing.T) {
+	verifier, challenge, err := oauth2PKCEPair()
+	if err != nil {
+		t.Fatalf("oauth2PKCEPair: %v", err)
+	}
+	if verifier == "" || challenge == "" {
+		t.Fatalf("expected non-empty verifier/challenge, got %q/%q", verifier, challenge)
+	}
+	if verifier == challenge {
+		t.Errorf("expected challenge to be derived from verifier, not equal to it")
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	if want := base64.RawURLEncoding.EncodeToString(sum[:]); challenge != want {
+		t.Errorf("challenge = %q, want base64url(sha256(verifier)) = %q", challenge, want)
+	}
+}
+
+func TestOAuth2ExtractCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedState string
+		want          string
+		wantErr       bool
+	}{
+		{"matching state", "http://localhost:8976/callback?state=abc&code=xyz123", "abc", "xyz123", false},
+		{"bare code has no state to check", "xyz123", "abc", "xyz123", false},
+		{"missing code", "http://localhost:8976/callback?state=abc", "abc", "", true},
+		{"mismatched state", "http://localhost:8976/callback?state=wrong&code=xyz123", "abc", "", true},
+		{"missing state", "http://localhost:8976/callback?code=xyz123", "abc", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := oauth2ExtractCode(tt.input, tt.expectedState)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("oauth2ExtractCode(%q, %q) err = %v, wantErr %v", tt.input, tt.expectedState, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("oauth2ExtractCode(%q, %q) = %q, want %q", tt.input, tt.expectedState, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuth2TokenCacheRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	creds := &OAuth2Credentials{
+		ClientID:     "client-123",
+		AccessToken:  "access-tok",
+		RefreshToken: "refresh-tok",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		CloudID:      "cloud-abc",
+	}
+	if err := saveOAuth2TokenCache("https://example.atlassian.net", creds); err != nil {
+		t.Fatalf("saveOAuth2TokenCache: %v", err)
+	}
+
+	cached, err := loadOAuth2TokenCache("https://example.atlassian.net", "client-123")
+	if err != nil {
+		t.Fatalf("loadOAuth2TokenCache: %v", err)
+	}
+	if cached.AccessToken != creds.AccessToken || cached.RefreshToken != creds.RefreshToken || cached.CloudID != creds.CloudID {
+		t.Errorf("cached = %+v, want fields from %+v", cached, creds)
+	}
+}
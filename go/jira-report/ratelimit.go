@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultRateLimit is the default cap on outgoing Jira requests per second.
+const defaultRateLimit = 10.0
+
+// RateLimiter is a simple token bucket: tokens refill continuously at
+// ratePerSecond, up to a burst of one second's worth, and Wait blocks until
+// one is available.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing ratePerSecond requests per
+// second on average, with a burst of up to ratePerSecond.
+func NewRateLimiter(ratePerSecond float64) *RateLimiter {
+	return &RateLimiter{rate: ratePerSecond, tokens: ratePerSecond, lastFill: time.Now()}
+}
+
+// Wait blocks until a token is available, then consumes it.
+func (r *RateLimiter) Wait() {
+	if r == nil {
+		return
+	}
+	for {
+		r.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(r.lastFill).Seconds()
+		r.lastFill = now
+		r.tokens = math.Min(r.rate, r.tokens+elapsed*r.rate)
+
+		if r.tokens >= 1 {
+			r.tokens--
+			r.mu.Unlock()
+			return
+		}
+
+		wait := time.Duration((1 - r.tokens) / r.rate * float64(time.Second))
+		r.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowsBurstThenThrottles(t *testing.T) {
+	r := NewRateLimiter(100) // 100/s, so a burst of 100 tokens is available immediately
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		r.Wait()
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected burst of 10 to be roughly instant, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterNilIsNoop(t *testing.T) {
+	var r *RateLimiter
+	r.Wait() // must not panic
+}
+
+func TestRetryDelayHonorsRetryAfterHeader(t *testing.T) {
+	got := retryDelay("2", 0)
+	if got != 2*time.Second {
+		t.Errorf("retryDelay(%q, 0) = %v, want 2s", "2", got)
+	}
+}
+
+func TestRetryDelayFallsBackToExponentialBackoff(t *testing.T) {
+	got := retryDelay("", 3)
+	if got != 8*time.Second {
+		t.Errorf("retryDelay(\"\", 3) = %v, want 8s", got)
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutputFormat selects which Renderer GenerateReport uses.
+type OutputFormat string
+
+const (
+	OutputFormatMarkdown OutputFormat = "md"
+	OutputFormatHTML     OutputFormat = "html"
+	OutputFormatJSON     OutputFormat = "json"
+	OutputFormatCSV      OutputFormat = "csv"
+	OutputFormatSlack    OutputFormat = "slack"
+)
+
+// ParseOutputFormat parses the --format flag value.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(s)) {
+	case OutputFormatMarkdown:
+		return OutputFormatMarkdown, nil
+	case OutputFormatHTML:
+		return OutputFormatHTML, nil
+	case OutputFormatJSON:
+		return OutputFormatJSON, nil
+	case OutputFormatCSV:
+		return OutputFormatCSV, nil
+	case OutputFormatSlack:
+		return OutputFormatSlack, nil
+	default:
+		return OutputFormatMarkdown, fmt.Errorf("unknown output format %q (want md|html|json|csv|slack)", s)
+	}
+}
+
+// RenderOptions bundles the parameters every Renderer needs to turn a set of
+// issues into a report. Since, Title, and PrevState mirror the arguments
+// RenderMarkdownReport has always taken.
+type RenderOptions struct {
+	ShowParent bool
+	Since      *time.Time
+	Title      string
+	PrevState  *StateStore
+}
+
+// Renderer turns a set of issues into a report in some output format.
+type Renderer interface {
+	Render(issues []IssueData, opts RenderOptions) ([]byte, error)
+
+	// Combine folds a freshly rendered report into whatever this renderer
+	// previously wrote to --output-file (existing is nil/empty the first
+	// time, or whenever the file didn't exist). Formats that can't just be
+	// concatenated - JSON and the Slack blocks payload - return an error
+	// instead of producing invalid output.
+	Combine(existing, report []byte) ([]byte, error)
+}
+
+// GetRenderer returns the Renderer for a parsed OutputFormat.
+func GetRenderer(format OutputFormat) (Renderer, error) {
+	switch format {
+	case OutputFormatMarkdown:
+		return MarkdownRenderer{}, nil
+	case OutputFormatHTML:
+		return HTMLRenderer{}, nil
+	case OutputFormatJSON:
+		return JSONRenderer{}, nil
+	case OutputFormatCSV:
+		return CSVRenderer{}, nil
+	case OutputFormatSlack:
+		return SlackRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// filterAndSortIssues applies the --since filter and the status/target-date/
+// updated/summary sort order shared by every Renderer.
+func filterAndSortIssues(issues []IssueData, since *time.Time) []IssueData {
+	var filtered []IssueData
+	for _, issue := range issues {
+		if since != nil {
+			timestamp := issue.Updated
+			if timestamp == "" || timestamp == "N/A" {
+				continue
+			}
+			updateDate, err := ParseJiraDate(timestamp)
+			if err != nil {
+				logWarning("Could not parse date '%s': %v", timestamp, err)
+				continue
+			}
+			if updateDate.Before(*since) {
+				continue
+			}
+		}
+		filtered = append(filtered, issue)
+	}
+
+	sortIssues(filtered)
+	return filtered
+}
+
+// sortIssues sorts in place by status priority, then target end, then
+// updated, then summary.
+func sortIssues(issues []IssueData) {
+	sort.Slice(issues, func(i, j int) bool {
+		pi := GetStatusPriority(issues[i].StatusName)
+		pj := GetStatusPriority(issues[j].StatusName)
+		if pi != pj {
+			return pi < pj
+		}
+
+		ti := issues[i].TargetEnd
+		tj := issues[j].TargetEnd
+		if ti == "" {
+			ti = "9999-99-99"
+		}
+		if tj == "" {
+			tj = "9999-99-99"
+		}
+		if ti != tj {
+			return ti < tj
+		}
+
+		ui := issues[i].Updated
+		uj := issues[j].Updated
+		if ui != uj {
+			return ui < uj
+		}
+
+		return issues[i].Summary < issues[j].Summary
+	})
+}
+
+// MarkdownRenderer renders the default markdown table report, unchanged from
+// RenderMarkdownReport, plus the changelog digest when Since is set.
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) Render(issues []IssueData, opts RenderOptions) ([]byte, error) {
+	report := RenderMarkdownReport(issues, opts.ShowParent, opts.Since, opts.Title, opts.PrevState)
+	if opts.Since != nil {
+		if section := RenderChangelogSection(issues, *opts.Since); section != "" {
+			report += section
+		}
+	}
+	return []byte(report), nil
+}
+
+// Combine joins successive markdown reports with the blank-line separator
+// RenderMarkdownReport's callers have always used when appending to a file.
+func (MarkdownRenderer) Combine(existing, report []byte) ([]byte, error) {
+	return combineWithBlankLines(existing, report), nil
+}
+
+// combineWithBlankLines is the shared append strategy for renderers whose
+// output is just as valid concatenated as it is standalone.
+func combineWithBlankLines(existing, report []byte) []byte {
+	if len(existing) == 0 {
+		return report
+	}
+	return append(append(existing, "\n\n\n\n"...), report...)
+}
+
+// statusPillColor returns the CSS background color for an issue's trending
+// category, matching the emoji it's reported with elsewhere.
+func statusPillColor(trending string) string {
+	switch trending {
+	case "done":
+		return "#9b59b6"
+	case "overdue", "off track", "blocked":
+		return "#e74c3c"
+	case "at risk":
+		return "#f1c40f"
+	case "not started":
+		return "#bdc3c7"
+	default:
+		return "#2ecc71" // on track / in progress
+	}
+}
+
+// HTMLRenderer renders issues as an HTML table with CSS-colored status
+// pills, suitable for embedding in a dashboard or CI comment.
+type HTMLRenderer struct{}
+
+func (HTMLRenderer) Render(issues []IssueData, opts RenderOptions) ([]byte, error) {
+	filtered := filterAndSortIssues(issues, opts.Since)
+
+	title := opts.Title
+	if title == "" {
+		title = "Jira Status Report"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h2>%s, %s</h2>\n", htmlEscape(title), time.Now().Format("2006-01-02"))
+	b.WriteString("<table>\n<thead><tr><th>status</th>")
+	if opts.ShowParent {
+		b.WriteString("<th>parent</th>")
+	}
+	b.WriteString("<th>issue</th><th>assignee</th><th>target date</th><th>last update</th></tr></thead>\n<tbody>\n")
+
+	for _, issue := range filtered {
+		pill := fmt.Sprintf(`<span class="status-pill" style="background-color:%s">%s %s</span>`,
+			statusPillColor(issue.Trending), issue.Emoji, htmlEscape(issue.Trending))
+		b.WriteString("<tr><td>" + pill + "</td>")
+		if opts.ShowParent {
+			fmt.Fprintf(&b, `<td><a href="%s">%s</a></td>`, issue.ParentURL, htmlEscape(issue.ParentKey))
+		}
+		fmt.Fprintf(&b, `<td><a href="%s">%s</a></td><td>%s</td><td>%s</td><td>%s</td></tr>`+"\n",
+			issue.URL, htmlEscape(issue.Summary), htmlEscape(issue.Assignee),
+			htmlEscape(FormatDate(issue.TargetEnd)), htmlEscape(FormatDate(issue.Updated)))
+	}
+	b.WriteString("</tbody>\n</table>\n")
+
+	if opts.Since != nil {
+		if section := renderChangelogHTML(filtered, *opts.Since); section != "" {
+			b.WriteString(section)
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// Combine joins successive HTML reports with a blank-line separator, same
+// as MarkdownRenderer - each Render call is a self-contained fragment.
+func (HTMLRenderer) Combine(existing, report []byte) ([]byte, error) {
+	return combineWithBlankLines(existing, report), nil
+}
+
+// renderChangelogHTML is the HTML equivalent of RenderChangelogSection.
+func renderChangelogHTML(issues []IssueData, since time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("<h3>What changed since %s</h3>\n<ul>\n", since.Format("2006-01-02")))
+	hasChanges := false
+	for _, issue := range issues {
+		var items []string
+		for _, entry := range issue.Changelog {
+			created, err := ParseJiraDate(entry.Created)
+			if err != nil || created.Before(since) {
+				continue
+			}
+			if entry.Field != "status" && entry.Field != "assignee" && !isTargetDateField(entry.Field) {
+				continue
+			}
+			items = append(items, fmt.Sprintf("<li>%s: %s &rarr; %s (%s)</li>",
+				htmlEscape(entry.Field), htmlEscape(entry.FromString), htmlEscape(entry.ToString), htmlEscape(entry.Author)))
+		}
+		if len(items) == 0 {
+			continue
+		}
+		hasChanges = true
+		fmt.Fprintf(&b, `<li><a href="%s">%s</a><ul>%s</ul></li>`+"\n", issue.URL, htmlEscape(issue.Summary), strings.Join(items, ""))
+	}
+	b.WriteString("</ul>\n")
+	if !hasChanges {
+		return ""
+	}
+	return b.String()
+}
+
+// htmlEscape escapes the handful of characters that matter inside the plain
+// table cells and list items this renderer produces.
+func htmlEscape(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return r.Replace(s)
+}
+
+// JSONRenderer renders issues as machine-readable JSON for downstream
+// tooling, including each issue's Changelog when it was populated.
+type JSONRenderer struct{}
+
+type jsonReport struct {
+	Title       string      `json:"title"`
+	GeneratedAt string      `json:"generated_at"`
+	Issues      []IssueData `json:"issues"`
+}
+
+func (JSONRenderer) Render(issues []IssueData, opts RenderOptions) ([]byte, error) {
+	filtered := filterAndSortIssues(issues, opts.Since)
+	report := jsonReport{
+		Title:       opts.Title,
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Issues:      filtered,
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling JSON report: %w", err)
+	}
+	return b, nil
+}
+
+// Combine refuses to append: concatenating two JSON documents isn't valid
+// JSON, and silently picking a wrapping scheme would surprise whatever
+// parses --output-file downstream. Callers that want repeated JSON runs
+// should write to a fresh file (or clear the existing one) each time.
+func (JSONRenderer) Combine(existing, report []byte) ([]byte, error) {
+	if len(existing) == 0 {
+		return report, nil
+	}
+	return nil, fmt.Errorf("--output-file already contains a JSON report; appending would produce invalid JSON (use a fresh --output-file, or remove the existing one, for each run)")
+}
+
+// CSVRenderer renders issues as a flat CSV table.
+type CSVRenderer struct{}
+
+func (CSVRenderer) Render(issues []IssueData, opts RenderOptions) ([]byte, error) {
+	filtered := filterAndSortIssues(issues, opts.Since)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"status", "key", "summary", "url", "assignee", "target_date", "last_update"}
+	if opts.ShowParent {
+		header = append([]string{"parent_key"}, header...)
+	}
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	for _, issue := range filtered {
+		row := []string{issue.Trending, issue.Key, issue.Summary, issue.URL, issue.Assignee, FormatDate(issue.TargetEnd), FormatDate(issue.Updated)}
+		if opts.ShowParent {
+			row = append([]string{issue.ParentKey}, row...)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, fmt.Errorf("writing CSV row for %s: %w", issue.Key, err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("flushing CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Combine appends subsequent CSV reports as plain rows, dropping their
+// header line so repeated runs don't write the header into the middle of
+// the file.
+func (CSVRenderer) Combine(existing, report []byte) ([]byte, error) {
+	if len(existing) == 0 {
+		return report, nil
+	}
+	if i := bytes.IndexByte(report, '\n'); i >= 0 {
+		report = report[i+1:]
+	}
+	return append(existing, report...), nil
+}
+
+// SlackRenderer renders issues as Slack Block Kit blocks suitable for
+// posting via an incoming webhook.
+type SlackRenderer struct{}
+
+func (SlackRenderer) Render(issues []IssueData, opts RenderOptions) ([]byte, error) {
+	filtered := filterAndSortIssues(issues, opts.Since)
+
+	title := opts.Title
+	if title == "" {
+		title = "Jira Status Report"
+	}
+
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": fmt.Sprintf("%s, %s", title, time.Now().Format("2006-01-02"))},
+		},
+	}
+
+	for _, issue := range filtered {
+		text := fmt.Sprintf("%s *%s* <%s|%s>\n%s · due %s · updated %s",
+			issue.Emoji, strings.ToUpper(issue.Trending), issue.URL, issue.Summary,
+			issue.Assignee, FormatDate(issue.TargetEnd), FormatDate(issue.Updated))
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": text},
+		})
+	}
+
+	if len(filtered) == 0 {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": "_No issues to report._"},
+		})
+	}
+
+	payload := map[string]any{"blocks": blocks}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling Slack blocks: %w", err)
+	}
+	return b, nil
+}
+
+// Combine refuses to append, for the same reason as JSONRenderer: the
+// Slack payload is itself a single JSON document, and PostSlackBlocks is
+// the intended sink for it rather than --output-file.
+func (SlackRenderer) Combine(existing, report []byte) ([]byte, error) {
+	if len(existing) == 0 {
+		return report, nil
+	}
+	return nil, fmt.Errorf("--output-file already contains a Slack blocks payload; appending would produce invalid JSON (use a fresh --output-file, or remove the existing one, for each run)")
+}
+
+// PostSlackBlocks POSTs rendered Slack Block Kit JSON to an incoming webhook
+// URL, as configured via --slack-webhook.
+func PostSlackBlocks(webhookURL string, blocks []byte) error {
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewReader(blocks))
+	if err != nil {
+		return fmt.Errorf("posting to Slack webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{"md", OutputFormatMarkdown, false},
+		{"HTML", OutputFormatHTML, false},
+		{"json", OutputFormatJSON, false},
+		{"csv", OutputFormatCSV, false},
+		{"slack", OutputFormatSlack, false},
+		{"yaml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOutputFormat(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOutputFormat(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseOutputFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSONRendererRoundTrips(t *testing.T) {
+	issues := []IssueData{
+		{Key: "A-1", Summary: "First", StatusName: "done", Trending: "done"},
+	}
+	out, err := JSONRenderer{}.Render(issues, RenderOptions{Title: "Test"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var report jsonReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("invalid JSON: %v (%s)", err, out)
+	}
+	if report.Title != "Test" {
+		t.Errorf("Title = %q, want Test", report.Title)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Key != "A-1" {
+		t.Errorf("Issues = %+v, want [A-1]", report.Issues)
+	}
+}
+
+func TestCSVRendererHeaderAndRow(t *testing.T) {
+	issues := []IssueData{
+		{Key: "A-1", Summary: "First", Trending: "done", Assignee: "Alice"},
+	}
+	out, err := CSVRenderer{}.Render(issues, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header + 1 row, got %d lines: %q", len(lines), out)
+	}
+	if !strings.Contains(lines[0], "status") || !strings.Contains(lines[0], "key") {
+		t.Errorf("header missing expected columns: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "A-1") || !strings.Contains(lines[1], "Alice") {
+		t.Errorf("row missing issue data: %q", lines[1])
+	}
+}
+
+func TestSlackRendererProducesBlocks(t *testing.T) {
+	issues := []IssueData{
+		{Key: "A-1", URL: "https://jira/a", Summary: "First", Trending: "done", Emoji: "🟣"},
+	}
+	out, err := SlackRenderer{}.Render(issues, RenderOptions{Title: "Status"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("invalid JSON: %v (%s)", err, out)
+	}
+	blocks, ok := payload["blocks"].([]any)
+	if !ok || len(blocks) != 2 {
+		t.Fatalf("expected a header block and one section block, got %+v", payload["blocks"])
+	}
+}
+
+func TestMarkdownAndHTMLRendererCombineInsertsBlankLines(t *testing.T) {
+	for _, r := range []Renderer{MarkdownRenderer{}, HTMLRenderer{}} {
+		combined, err := r.Combine([]byte("first report"), []byte("second report"))
+		if err != nil {
+			t.Fatalf("%T Combine: %v", r, err)
+		}
+		if string(combined) != "first report\n\n\n\nsecond report" {
+			t.Errorf("%T Combine = %q, want blank-line separated", r, combined)
+		}
+
+		first, err := r.Combine(nil, []byte("first report"))
+		if err != nil {
+			t.Fatalf("%T Combine(nil, ...): %v", r, err)
+		}
+		if string(first) != "first report" {
+			t.Errorf("%T Combine(nil, ...) = %q, want the report unchanged", r, first)
+		}
+	}
+}
+
+func TestCSVRendererCombineDropsRepeatedHeader(t *testing.T) {
+	existing := "status,key\ndone,A-1\n"
+	next := "status,key\nopen,A-2\n"
+
+	combined, err := CSVRenderer{}.Combine([]byte(existing), []byte(next))
+	if err != nil {
+		t.Fatalf("Combine: %v", err)
+	}
+	if strings.Count(string(combined), "status,key") != 1 {
+		t.Errorf("Combine = %q, want exactly one header line", combined)
+	}
+	if !strings.Contains(string(combined), "open,A-2") {
+		t.Errorf("Combine = %q, want the new row appended", combined)
+	}
+}
+
+func TestJSONAndSlackRendererCombineRejectsAppend(t *testing.T) {
+	for _, r := range []Renderer{JSONRenderer{}, SlackRenderer{}} {
+		if _, err := r.Combine([]byte("{}"), []byte("{}")); err == nil {
+			t.Errorf("%T Combine with non-empty existing content: expected an error, got nil", r)
+		}
+		if out, err := r.Combine(nil, []byte(`{"a":1}`)); err != nil || string(out) != `{"a":1}` {
+			t.Errorf("%T Combine(nil, ...) = (%q, %v), want the report unchanged with no error", r, out, err)
+		}
+	}
+}
+
+func TestHTMLRendererEscapesAndColorsStatus(t *testing.T) {
+	issues := []IssueData{
+		{Key: "A-1", URL: "https://jira/a", Summary: "<script>", Trending: "blocked", Emoji: "🔴"},
+	}
+	out, err := HTMLRenderer{}.Render(issues, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	html := string(out)
+	if strings.Contains(html, "<script>") {
+		t.Errorf("expected summary to be HTML-escaped: %s", html)
+	}
+	if !strings.Contains(html, "#e74c3c") {
+		t.Errorf("expected blocked status to use its pill color: %s", html)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultSearchWorkers is how many goroutines SearchIssuesConcurrent uses
+// when the caller doesn't ask for a specific number.
+const defaultSearchWorkers = 4
+
+// searchFields returns the field list used by SearchIssues and
+// SearchIssuesConcurrent, loading custom field IDs first.
+func (c *JiraClient) searchFields() string {
+	fields := "summary,status,assignee,priority,created,updated"
+
+	if err := c.LoadCustomFields(customFields); err != nil {
+		logWarning("Could not load custom fields: %v", err)
+	}
+
+	for _, id := range customFields {
+		if id != "" {
+			fields += "," + id
+		}
+	}
+
+	return fields
+}
+
+// searchPage is one page of search results, tagged with its position in the
+// overall result set so pages can be reassembled in order.
+type searchPage struct {
+	startAt int
+	issues  []map[string]any
+	total   int
+	err     error
+}
+
+// SearchIssuesConcurrent searches for issues using JQL, like SearchIssues, but
+// fetches pages in parallel across workers goroutines instead of strictly
+// serially. It issues one probe request to learn the server-side total, fans
+// the remaining pages out across the workers, and reassembles them in order.
+// workers defaults to defaultSearchWorkers when <= 0.
+func (c *JiraClient) SearchIssuesConcurrent(jql string, maxResults, workers int) ([]map[string]any, error) {
+	if workers <= 0 {
+		workers = defaultSearchWorkers
+	}
+
+	fields := c.searchFields()
+	pageSize := min(defaultPageSize, maxResults)
+
+	logDebug("Probing issue count: startAt=0, maxResults=%d", pageSize)
+	probe := c.searchPage(jql, fields, 0, pageSize)
+	if probe.err != nil {
+		return nil, probe.err
+	}
+
+	total := probe.total
+	if total > maxResults {
+		total = maxResults
+	}
+
+	jobs := make(chan int)
+	results := make(chan searchPage)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for startAt := range jobs {
+				size := min(pageSize, total-startAt)
+				results <- c.searchPage(jql, fields, startAt, size)
+			}
+		}()
+	}
+
+	go func() {
+		for startAt := pageSize; startAt < total; startAt += pageSize {
+			jobs <- startAt
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int][]map[string]any)
+	pending[0] = probe.issues
+
+	var firstErr error
+	for page := range results {
+		if page.err != nil && firstErr == nil {
+			firstErr = page.err
+			continue
+		}
+		pending[page.startAt] = page.issues
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var allIssues []map[string]any
+	for startAt := 0; startAt < total; startAt += pageSize {
+		allIssues = append(allIssues, pending[startAt]...)
+	}
+
+	logInfo("Fetched %d issues total (%d workers)", len(allIssues), workers)
+	if len(allIssues) > maxResults {
+		return allIssues[:maxResults], nil
+	}
+	return allIssues, nil
+}
+
+// searchPage fetches a single page of search results starting at startAt.
+func (c *JiraClient) searchPage(jql, fields string, startAt, maxResults int) searchPage {
+	params := map[string]string{
+		"jql":        jql,
+		"fields":     fields,
+		"startAt":    fmt.Sprintf("%d", startAt),
+		"maxResults": fmt.Sprintf("%d", maxResults),
+	}
+
+	logDebug("Fetching issues: startAt=%d, maxResults=%d", startAt, maxResults)
+	response, err := c.Get("search", params)
+	if err != nil {
+		return searchPage{startAt: startAt, err: err}
+	}
+
+	return searchPage{
+		startAt: startAt,
+		issues:  getMapList(response, "issues"),
+		total:   getInt(response, "total"),
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newSearchTestServer serves /rest/api/2/field and /rest/api/2/search,
+// paginating totalIssues fake issues keyed by their startAt offset.
+func newSearchTestServer(t *testing.T, totalIssues int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+
+		end := startAt + maxResults
+		if end > totalIssues {
+			end = totalIssues
+		}
+
+		issues := ""
+		for i := startAt; i < end; i++ {
+			if issues != "" {
+				issues += ","
+			}
+			issues += fmt.Sprintf(`{"key":"PROJ-%d"}`, i)
+		}
+
+		fmt.Fprintf(w, `{"total":%d,"issues":[%s]}`, totalIssues, issues)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newTestClient(server *httptest.Server) *JiraClient {
+	return &JiraClient{
+		Server:     server.URL,
+		APIVersion: "2",
+		AuthMode:   AuthBasic,
+		HTTPClient: server.Client(),
+		RateLimit:  NewRateLimiter(1000),
+	}
+}
+
+func TestSearchIssuesConcurrentReassemblesInOrder(t *testing.T) {
+	server := newSearchTestServer(t, 137)
+	defer server.Close()
+
+	client := newTestClient(server)
+	issues, err := client.SearchIssuesConcurrent("project = PROJ", 137, 4)
+	if err != nil {
+		t.Fatalf("SearchIssuesConcurrent: %v", err)
+	}
+	if len(issues) != 137 {
+		t.Fatalf("got %d issues, want 137", len(issues))
+	}
+	for i, issue := range issues {
+		want := fmt.Sprintf("PROJ-%d", i)
+		if got := getString(issue, "key"); got != want {
+			t.Errorf("issue %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestSearchIssuesConcurrentTruncatesToMaxResults(t *testing.T) {
+	server := newSearchTestServer(t, 200)
+	defer server.Close()
+
+	client := newTestClient(server)
+	issues, err := client.SearchIssuesConcurrent("project = PROJ", 30, 4)
+	if err != nil {
+		t.Fatalf("SearchIssuesConcurrent: %v", err)
+	}
+	if len(issues) != 30 {
+		t.Fatalf("got %d issues, want 30", len(issues))
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stateMagic/stateSchemaVersion identify the on-disk format of a StateStore
+// so a future format change can be detected instead of silently misparsed.
+const (
+	stateMagic         = "JRS1"
+	stateSchemaVersion = 1
+)
+
+// StateStore is a versioned snapshot of the last run's issues, keyed by issue
+// key, used to compute a "what changed" section on the next run and, via
+// QueryHash/Watermark, to fetch only what changed since then.
+type StateStore struct {
+	Magic         string               `json:"magic"`
+	SchemaVersion int                  `json:"schema_version"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+	JiraBaseURL   string               `json:"jira_base_url"`
+	QueryHash     string               `json:"query_hash"`
+	Watermark     string               `json:"watermark"`
+	Issues        map[string]IssueData `json:"issues"`
+}
+
+// DefaultStateFilePath returns the state file jira-report uses when
+// --state-file isn't given: $XDG_STATE_HOME/jira-report/state.json, falling
+// back to ~/.local/state per the XDG base directory spec when
+// XDG_STATE_HOME is unset.
+func DefaultStateFilePath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolving default state file: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "jira-report", "state.json"), nil
+}
+
+// QueryHash identifies the scope of issues a report run covers (a JQL query,
+// or an explicit set of issue keys), so a loaded StateStore can tell whether
+// its Watermark still applies to the current run or was computed against a
+// different query and should be ignored.
+func QueryHash(server, jql string, issueKeys []string) string {
+	keys := append([]string(nil), issueKeys...)
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(server + "|" + jql + "|" + strings.Join(keys, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadStateStore reads a StateStore from path. A missing file is not an
+// error: it just means there's nothing to diff against yet.
+func LoadStateStore(path string) (*StateStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var s StateStore
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing state file %s: %w", path, err)
+	}
+	if s.Magic != stateMagic {
+		return nil, fmt.Errorf("state file %s is not a recognized jira-report state file", path)
+	}
+	if s.SchemaVersion != stateSchemaVersion {
+		return nil, fmt.Errorf("state file %s has unsupported schema version %d", path, s.SchemaVersion)
+	}
+	return &s, nil
+}
+
+// SaveStateStore writes the current set of issues to path, keyed by issue
+// key, along with the query scope and watermark the next run needs to fetch
+// incrementally.
+func SaveStateStore(path, jiraBaseURL, queryHash, watermark string, issues []IssueData) error {
+	byKey := make(map[string]IssueData, len(issues))
+	for _, issue := range issues {
+		byKey[issue.Key] = issue
+	}
+
+	s := StateStore{
+		Magic:         stateMagic,
+		SchemaVersion: stateSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		JiraBaseURL:   jiraBaseURL,
+		QueryHash:     queryHash,
+		Watermark:     watermark,
+		Issues:        byKey,
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state file directory for %s: %w", path, err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// MergeIncremental overlays fresh on top of baseline (keyed by Key). It's
+// used when a watermark-filtered run only re-fetched issues that changed
+// since the previous one: anything in baseline but not in fresh is assumed
+// still current and carried over as-is.
+func MergeIncremental(baseline map[string]IssueData, fresh []IssueData) []IssueData {
+	merged := make(map[string]IssueData, len(baseline)+len(fresh))
+	for key, issue := range baseline {
+		merged[key] = issue
+	}
+	for _, issue := range fresh {
+		merged[issue.Key] = issue
+	}
+
+	result := make([]IssueData, 0, len(merged))
+	for _, issue := range merged {
+		result = append(result, issue)
+	}
+	return result
+}
+
+// MaxUpdated returns the latest fields.updated timestamp across issues,
+// reformatted for use in a JQL `updated >= "..."` clause on the next run.
+// It returns "" if none of the issues have a parseable Updated timestamp.
+func MaxUpdated(issues []IssueData) string {
+	var max time.Time
+	found := false
+	for _, issue := range issues {
+		t, err := ParseJiraDate(issue.Updated)
+		if err != nil {
+			continue
+		}
+		if !found || t.After(max) {
+			max = t
+			found = true
+		}
+	}
+	if !found {
+		return ""
+	}
+	return max.UTC().Format("2006-01-02 15:04")
+}
+
+// IssueChange describes one detected difference between a prior StateStore
+// and the current set of issues.
+type IssueChange struct {
+	Key     string
+	Summary string
+	URL     string
+	Kind    string // "new", "removed", "status", "assignee", "target_slipped"
+	From    string
+	To      string
+}
+
+// ComputeChanges diffs the current issues against prev, returning one
+// IssueChange per detected transition. A nil prev yields no changes, since
+// there's nothing to compare against on a first run.
+func ComputeChanges(prev *StateStore, current []IssueData) []IssueChange {
+	if prev == nil {
+		return nil
+	}
+
+	currentByKey := make(map[string]IssueData, len(current))
+	for _, issue := range current {
+		currentByKey[issue.Key] = issue
+	}
+
+	var changes []IssueChange
+	for key, issue := range currentByKey {
+		prevIssue, existed := prev.Issues[key]
+		if !existed {
+			changes = append(changes, IssueChange{Key: key, Summary: issue.Summary, URL: issue.URL, Kind: "new"})
+			continue
+		}
+		if prevIssue.StatusName != issue.StatusName {
+			changes = append(changes, IssueChange{
+				Key: key, Summary: issue.Summary, URL: issue.URL,
+				Kind: "status", From: prevIssue.StatusName, To: issue.StatusName,
+			})
+		}
+		if prevIssue.Assignee != issue.Assignee {
+			changes = append(changes, IssueChange{
+				Key: key, Summary: issue.Summary, URL: issue.URL,
+				Kind: "assignee", From: prevIssue.Assignee, To: issue.Assignee,
+			})
+		}
+		if prevIssue.TargetEnd != issue.TargetEnd && targetSlipped(prevIssue.TargetEnd, issue.TargetEnd) {
+			changes = append(changes, IssueChange{
+				Key: key, Summary: issue.Summary, URL: issue.URL,
+				Kind: "target_slipped", From: prevIssue.TargetEnd, To: issue.TargetEnd,
+			})
+		}
+	}
+
+	for key, prevIssue := range prev.Issues {
+		if _, stillPresent := currentByKey[key]; !stillPresent {
+			changes = append(changes, IssueChange{Key: key, Summary: prevIssue.Summary, URL: prevIssue.URL, Kind: "removed"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Key != changes[j].Key {
+			return changes[i].Key < changes[j].Key
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// targetSlipped reports whether to is a later target date than from.
+func targetSlipped(from, to string) bool {
+	if from == "" || to == "" {
+		return false
+	}
+	fromDate, err := ParseJiraDate(from)
+	if err != nil {
+		return false
+	}
+	toDate, err := ParseJiraDate(to)
+	if err != nil {
+		return false
+	}
+	return toDate.After(fromDate)
+}
+
+// TrendingFromDelta computes a "since last run" trend for an issue that
+// existed in the previous state, instead of deriving Trending from the
+// current status alone.
+func TrendingFromDelta(prev, current IssueData) string {
+	if current.StatusName != prev.StatusName {
+		if GetStatusPriority(current.StatusName) < GetStatusPriority(prev.StatusName) {
+			return "improving"
+		}
+		return "regressed"
+	}
+	if targetSlipped(prev.TargetEnd, current.TargetEnd) {
+		return "slipped"
+	}
+	return "stable"
+}
+
+// RenderChangesSection renders a "Changes since <timestamp>" markdown block
+// from a set of computed IssueChanges. It returns "" when there's nothing to
+// report, so callers can skip appending an empty section.
+func RenderChangesSection(changes []IssueChange, since time.Time) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("\n### Changes since %s", since.Format("2006-01-02")))
+	for _, c := range changes {
+		link := fmt.Sprintf("[%s](%s)", c.Key, c.URL)
+		switch c.Kind {
+		case "new":
+			lines = append(lines, fmt.Sprintf("- %s: new issue \"%s\"", link, c.Summary))
+		case "removed":
+			lines = append(lines, fmt.Sprintf("- %s: no longer in the report (\"%s\")", link, c.Summary))
+		case "status":
+			lines = append(lines, fmt.Sprintf("- %s: %s → %s", link, c.From, c.To))
+		case "assignee":
+			lines = append(lines, fmt.Sprintf("- %s: reassigned from %s to %s", link, c.From, c.To))
+		case "target_slipped":
+			lines = append(lines, fmt.Sprintf("- %s: target date slipped from %s to %s", link, FormatDate(c.From), FormatDate(c.To)))
+		}
+	}
+	return "\n" + joinLines(lines)
+}
+
+func joinLines(lines []string) string {
+	out := lines[0]
+	for _, l := range lines[1:] {
+		out += "\n" + l
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+func TestQueryHashStableForSameInputs(t *testing.T) {
+	a := QueryHash("https://jira.example.com", "project = PROJ", nil)
+	b := QueryHash("https://jira.example.com", "project = PROJ", nil)
+	if a != b {
+		t.Errorf("QueryHash is not stable: %q != %q", a, b)
+	}
+}
+
+func TestQueryHashIgnoresIssueKeyOrder(t *testing.T) {
+	a := QueryHash("https://jira.example.com", "", []string{"A-1", "B-2"})
+	b := QueryHash("https://jira.example.com", "", []string{"B-2", "A-1"})
+	if a != b {
+		t.Errorf("QueryHash should be order-independent: %q != %q", a, b)
+	}
+}
+
+func TestQueryHashDiffersOnQueryChange(t *testing.T) {
+	a := QueryHash("https://jira.example.com", "project = PROJ", nil)
+	b := QueryHash("https://jira.example.com", "project = OTHER", nil)
+	if a == b {
+		t.Error("QueryHash should differ when the JQL changes")
+	}
+}
+
+func TestMergeIncrementalCarriesOverUnfetchedIssues(t *testing.T) {
+	baseline := map[string]IssueData{
+		"A-1": {Key: "A-1", Summary: "Unchanged"},
+		"A-2": {Key: "A-2", Summary: "Stale copy, should be overwritten"},
+	}
+	fresh := []IssueData{
+		{Key: "A-2", Summary: "Updated"},
+		{Key: "A-3", Summary: "New"},
+	}
+
+	merged := MergeIncremental(baseline, fresh)
+	byKey := make(map[string]IssueData, len(merged))
+	for _, issue := range merged {
+		byKey[issue.Key] = issue
+	}
+
+	if len(byKey) != 3 {
+		t.Fatalf("got %d merged issues, want 3", len(byKey))
+	}
+	if byKey["A-1"].Summary != "Unchanged" {
+		t.Errorf("A-1 = %q, want carried over from baseline", byKey["A-1"].Summary)
+	}
+	if byKey["A-2"].Summary != "Updated" {
+		t.Errorf("A-2 = %q, want overwritten by fresh", byKey["A-2"].Summary)
+	}
+	if byKey["A-3"].Summary != "New" {
+		t.Errorf("A-3 = %q, want the new issue present", byKey["A-3"].Summary)
+	}
+}
+
+func TestMaxUpdatedPicksLatestParseableTimestamp(t *testing.T) {
+	issues := []IssueData{
+		{Key: "A-1", Updated: "2024-01-01T10:00:00.000-0700"},
+		{Key: "A-2", Updated: "2024-03-15T08:30:00.000-0700"},
+		{Key: "A-3", Updated: "not a date"},
+	}
+	got := MaxUpdated(issues)
+	want := "2024-03-15 15:30" // normalized to UTC
+	if got != want {
+		t.Errorf("MaxUpdated = %q, want %q", got, want)
+	}
+}
+
+func TestMaxUpdatedEmptyWhenNothingParses(t *testing.T) {
+	issues := []IssueData{{Key: "A-1", Updated: "not a date"}}
+	if got := MaxUpdated(issues); got != "" {
+		t.Errorf("MaxUpdated = %q, want empty", got)
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"math"
 	"os"
 	"strings"
 	"time"
@@ -17,14 +16,155 @@ func load_file(path string, delimiter string) []string {
 	return strings.Split(strings.ToLower(string(contents)), delimiter)
 }
 
-func is_real_word(all_words []string, s string) bool {
-	// the word is real if it exists in the list!
-	for _, value := range all_words {
-		if s == value {
-			return true
+// Dictionary indexes a word list for fast membership checks and fuzzy lookups.
+type Dictionary struct {
+	words  map[string]struct{}
+	byPair map[string][]string // bucketed by the first two letters of each word
+}
+
+// NewDictionary builds a Dictionary from a word list.
+func NewDictionary(wordList []string) *Dictionary {
+	d := &Dictionary{
+		words:  make(map[string]struct{}, len(wordList)),
+		byPair: make(map[string][]string),
+	}
+	for _, w := range wordList {
+		if w == "" {
+			continue
+		}
+		d.words[w] = struct{}{}
+		key := pairKey(w)
+		d.byPair[key] = append(d.byPair[key], w)
+	}
+	return d
+}
+
+func pairKey(w string) string {
+	if len(w) < 2 {
+		return w
+	}
+	return w[:2]
+}
+
+// Contains reports whether word is present in the dictionary.
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.words[word]
+	return ok
+}
+
+// Prefix returns every dictionary word that starts with p.
+func (d *Dictionary) Prefix(p string) []string {
+	var matches []string
+	for w := range d.words {
+		if strings.HasPrefix(w, p) {
+			matches = append(matches, w)
+		}
+	}
+	return matches
+}
+
+// Neighbors returns dictionary words within maxEdits of word (Damerau-Levenshtein).
+// To keep this cheap it only searches the buckets candidatePairKeys lists, so a
+// word whose first two letters differ from word's by an insertion, a deletion,
+// or more than one edit is not returned.
+func (d *Dictionary) Neighbors(word string, maxEdits int) []string {
+	var candidates []string
+	seen := make(map[string]struct{})
+	for _, key := range candidatePairKeys(word) {
+		for _, w := range d.byPair[key] {
+			if _, ok := seen[w]; ok {
+				continue
+			}
+			seen[w] = struct{}{}
+			if w != word && damerauLevenshtein(word, w) <= maxEdits {
+				candidates = append(candidates, w)
+			}
+		}
+	}
+	return candidates
+}
+
+// candidatePairKeys returns every pairKey a neighbor of word could be bucketed
+// under: word's own key, its transposition (for a transposed first pair), and
+// every single-character substitution of either letter (for a plain
+// substitution landing in the first two characters).
+func candidatePairKeys(w string) []string {
+	base := pairKey(w)
+	if len(base) < 2 {
+		return []string{base}
+	}
+
+	keys := []string{base, swapPairKey(w)}
+	for c := byte('a'); c <= 'z'; c++ {
+		if c != base[0] {
+			keys = append(keys, string(c)+base[1:])
+		}
+		if c != base[1] {
+			keys = append(keys, base[:1]+string(c))
 		}
 	}
-	return false
+	return keys
+}
+
+// swapPairKey covers transposed first-letter pairs, since a single transposition
+// at the start of the word would otherwise land Neighbors in the wrong bucket.
+func swapPairKey(w string) string {
+	if len(w) < 2 {
+		return w
+	}
+	b := []byte(w[:2])
+	b[0], b[1] = b[1], b[0]
+	return string(b)
+}
+
+// damerauLevenshtein computes the edit distance between a and b, counting
+// insertions, deletions, substitutions, and adjacent transpositions as one edit each.
+func damerauLevenshtein(a, b string) int {
+	la, lb := len(a), len(b)
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(
+				d[i-1][j]+1,      // deletion
+				d[i][j-1]+1,      // insertion
+				d[i-1][j-1]+cost, // substitution
+			)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+cost) // transposition
+			}
+		}
+	}
+	return d[la][lb]
+}
+
+func min3(a, b, c int) int {
+	return min(a, min(b, c))
+}
+
+// splice_candidates enumerates every prefix-of-a + suffix-of-b combination at
+// each boundary within the word, for every ordered pair drawn from words.
+func splice_candidates(words []string) []string {
+	var candidates []string
+	for _, a := range words {
+		for _, b := range words {
+			for split := 1; split < len(a); split++ {
+				candidates = append(candidates, a[:split]+b[split:])
+			}
+		}
+	}
+	return candidates
 }
 
 func main() {
@@ -34,23 +174,18 @@ func main() {
 	end_time := time.Now()
 	fmt.Println("loaded", len(all_words), "words in", int(end_time.UnixMilli())-int(start_time.UnixMilli()), "ms")
 
-	// generate new words
+	dictionary := NewDictionary(all_words)
+
+	// generate new words by splicing at every boundary, not just the first letter
 	my_words := []string{
 		"fizz",
 		"buzz"}
-	my_words_count := len(my_words)
-	new_words := make([]string, int(math.Pow(float64(my_words_count), 2)))
-	for i, first_word := range my_words {
-		first_letter := first_word[:1]
-		for j, second_word := range my_words {
-			array_index := i*my_words_count + j
-			new_words[array_index] = first_letter + second_word[1:]
-		}
-	}
 
-	for _, value := range new_words {
-		if is_real_word(all_words, value) {
+	for _, value := range splice_candidates(my_words) {
+		if dictionary.Contains(value) {
 			fmt.Println(value, "IS a real word!")
+		} else if near := dictionary.Neighbors(value, 2); len(near) > 0 {
+			fmt.Println(value, "is NOT a real word, but close to:", near)
 		} else {
 			fmt.Println(value, "is NOT a real word.")
 		}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func buildTestWordList(n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%06d", i)
+	}
+	return words
+}
+
+func linearContains(words []string, s string) bool {
+	for _, w := range words {
+		if w == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDictionaryContains(t *testing.T) {
+	d := NewDictionary([]string{"fizz", "buzz", "fuzz"})
+	if !d.Contains("fizz") {
+		t.Error("expected fizz to be in the dictionary")
+	}
+	if d.Contains("nope") {
+		t.Error("did not expect nope to be in the dictionary")
+	}
+}
+
+func TestDictionaryNeighbors(t *testing.T) {
+	d := NewDictionary([]string{"fizz", "buzz", "fuzz", "jazz"})
+	near := d.Neighbors("fuzz", 1)
+	found := false
+	for _, w := range near {
+		if w == "fizz" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected fizz among Neighbors(fuzz, 1), got %v", near)
+	}
+}
+
+func BenchmarkLinearContains(b *testing.B) {
+	words := buildTestWordList(50000)
+	needle := words[len(words)-1]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearContains(words, needle)
+	}
+}
+
+func BenchmarkDictionaryContains(b *testing.B) {
+	words := buildTestWordList(50000)
+	needle := words[len(words)-1]
+	d := NewDictionary(words)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Contains(needle)
+	}
+}
@@ -1,13 +1,71 @@
 package main
 
-type Job struct {
+import (
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Job is anything that can be executed against a context and yields a Result.
+type Job interface {
+	ID() string
+	Do(ctx context.Context) (Result, error)
+
+	// SpoolPayload returns the data the dispatcher persists to the spool log
+	// for this job. Any Job implementation can be spooled, but WithSpool
+	// replays an interrupted job as a *PrintJob carrying this payload,
+	// whatever the job's original type.
+	SpoolPayload() string
+}
+
+// Result is whatever a Job produces.
+type Result struct {
+	JobID   string
 	Payload string
 }
 
-func (*Job) DoIt() {
-	print("completed job", job.Payload)
+// JobStatus tracks where a job is in its lifecycle, both in memory and in the spool.
+type JobStatus int
+
+const (
+	StatusInFlight JobStatus = iota
+	StatusComplete
+	StatusFailed
+)
+
+// JobResult is what callers receive off the Dispatcher's Results channel.
+type JobResult struct {
+	JobID  string
+	Result Result
+	Err    error
+}
+
+// PrintJob is a trivial Job: it prints its payload and echoes it back as the Result.
+type PrintJob struct {
+	JobId   string
+	Payload string
 }
 
+func (j *PrintJob) ID() string { return j.JobId }
+
+func (j *PrintJob) SpoolPayload() string { return j.Payload }
+
+func (j *PrintJob) Do(ctx context.Context) (Result, error) {
+	select {
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	default:
+	}
+	defaultLogger.With("component", "dispatcher").Info().Str("job_id", j.JobId).Str("payload", j.Payload).Msg("completed job")
+	return Result{JobID: j.JobId, Payload: j.Payload}, nil
+}
+
+// Worker pulls jobs from its JobChannel and runs them until told to quit.
 type Worker struct {
 	WorkerPool chan chan Job
 	JobChannel chan Job
@@ -22,30 +80,39 @@ func NewWorker(workerPool chan chan Job) *Worker {
 	}
 }
 
-// A worker will wait for work then do it.
-// It will do this until it receives the quit signal.
-func (w *Worker) Start() {
-	// the goroutine is launched from inside this function
+// Start launches the worker's run loop. It registers itself in the pool, waits
+// for a job, a quit signal, or context cancellation, and reports every
+// outcome on results. wg is marked done once the run loop exits, so callers
+// can wait for the worker to fully stop.
+func (w *Worker) Start(ctx context.Context, results chan<- JobResult, wg *sync.WaitGroup) {
 	go func() {
+		defer wg.Done()
 		for {
 			// register the current worker into the worker queue.
-			w.WorkerPool <- w.JobChannel
+			select {
+			case w.WorkerPool <- w.JobChannel:
+			case <-ctx.Done():
+				return
+			}
 
-			// wait for either a job or a quit signal
 			select {
 			case job := <-w.JobChannel:
 				// we have received a job to do
-				job.DoIt()
+				result, err := job.Do(ctx)
+				results <- JobResult{JobID: job.ID(), Result: result, Err: err}
 
 			case <-w.quit:
 				// we have received a signal to stop
 				return
+
+			case <-ctx.Done():
+				return
 			}
 		}
 	}()
 }
 
-// Send the quit signal to the worker.
+// Stop sends the quit signal to the worker.
 func (w *Worker) Stop() {
 	go func() {
 		w.quit <- true // send a "true" to the quit channel over in the running goroutine
@@ -55,45 +122,272 @@ func (w *Worker) Stop() {
 // A buffered channel that we can send work requests on.
 var JobQueue chan Job
 
+// spoolMagic/spoolVersion identify the on-disk binary log format written by WithSpool.
+const (
+	spoolMagic   uint32 = 0x53504C31 // "SPL1"
+	spoolVersion uint16 = 1
+)
+
+// spoolRecord is a single entry in the append-only spool log. Replay only
+// trusts the newest record per ID, so completion doesn't need to rewrite
+// the original record in place.
+type spoolRecord struct {
+	ID        string
+	Payload   string
+	Timestamp time.Time
+	Status    JobStatus
+}
+
+// Spool is an append-only binary log of accepted jobs, used to resume work
+// that was still in flight when a Dispatcher was killed.
+type Spool struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *gob.Encoder
+}
+
+// OpenSpool opens (creating if necessary) the spool file at path, writing its
+// header if the file is new.
+func OpenSpool(path string) (*Spool, error) {
+	_, statErr := os.Stat(path)
+	isNew := os.IsNotExist(statErr)
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single long-lived Encoder is required: gob only transmits a type's
+	// wire descriptor once per Encoder, so a fresh Encoder per record would
+	// have every record after the first rejected by the Decoder with
+	// "duplicate type received".
+	s := &Spool{file: f, enc: gob.NewEncoder(f)}
+	if isNew {
+		if err := s.writeHeader(); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *Spool) writeHeader() error {
+	if err := binary.Write(s.file, binary.BigEndian, spoolMagic); err != nil {
+		return err
+	}
+	return binary.Write(s.file, binary.BigEndian, spoolVersion)
+}
+
+// Append writes a new in-flight record for a job.
+func (s *Spool) Append(id, payload string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Seek(0, os.SEEK_END); err != nil {
+		return err
+	}
+	rec := spoolRecord{ID: id, Payload: payload, Timestamp: time.Now(), Status: StatusInFlight}
+	return s.enc.Encode(rec)
+}
+
+// MarkComplete appends a terminal record for id.
+func (s *Spool) MarkComplete(id string, status JobStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Seek(0, os.SEEK_END); err != nil {
+		return err
+	}
+	rec := spoolRecord{ID: id, Timestamp: time.Now(), Status: status}
+	return s.enc.Encode(rec)
+}
+
+// PendingJobs replays the spool and returns every record whose last known
+// status is still in-flight.
+func (s *Spool) PendingJobs() ([]spoolRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+
+	var magic uint32
+	var version uint16
+	if err := binary.Read(s.file, binary.BigEndian, &magic); err != nil {
+		return nil, err
+	}
+	if magic != spoolMagic {
+		return nil, fmt.Errorf("spool: bad magic %x", magic)
+	}
+	if err := binary.Read(s.file, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]spoolRecord)
+	dec := gob.NewDecoder(s.file)
+	for {
+		var rec spoolRecord
+		if err := dec.Decode(&rec); err != nil {
+			break // EOF, or trailing bytes from a torn write; stop replaying
+		}
+		latest[rec.ID] = rec
+	}
+
+	var pending []spoolRecord
+	for _, rec := range latest {
+		if rec.Status == StatusInFlight {
+			pending = append(pending, rec)
+		}
+	}
+	return pending, nil
+}
+
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Dispatcher owns a pool of workers and hands them jobs from JobQueue.
 type Dispatcher struct {
 	WorkerPool chan chan Job // this is a channel on which to receive a channel of jobs
+	Results    chan JobResult
+	workers    []*Worker
+	spool      *Spool
+	rawResults chan JobResult // workers publish here; forwarded to Results after any spool bookkeeping
+	workersWg  sync.WaitGroup // tracks only the workers, so rawResults can be closed once they've all exited
+	wg         sync.WaitGroup // tracks the dispatch loop, the forwarder, and the rawResults closer
 }
 
 func NewDispatcher(maxWorkers int) *Dispatcher {
-	pool := make(chan chan Job, maxWorkers)
-	return &Dispatcher{WorkerPool: pool}
+	return &Dispatcher{
+		WorkerPool: make(chan chan Job, maxWorkers),
+		Results:    make(chan JobResult, maxWorkers),
+		rawResults: make(chan JobResult, maxWorkers),
+	}
 }
 
-func (d *Dispatcher) dispatch() {
+// WithSpool attaches a persistent spool at path to the dispatcher, replaying
+// any jobs left in-flight by a previous run back into JobQueue.
+func (d *Dispatcher) WithSpool(path string) error {
+	spool, err := OpenSpool(path)
+	if err != nil {
+		return err
+	}
+	d.spool = spool
+
+	pending, err := spool.PendingJobs()
+	if err != nil {
+		return err
+	}
+	for _, rec := range pending {
+		JobQueue <- &PrintJob{JobId: rec.ID, Payload: rec.Payload}
+	}
+	return nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
 	for {
 		select {
 		case job := <-JobQueue:
 			// a job request has been received
+			if d.spool != nil {
+				d.spool.Append(job.ID(), job.SpoolPayload())
+			}
 			go func(job Job) {
 				// try to obtain a worker job channel that is available.
-				// this will block until a worker is idle
-				jobChannel := <-d.WorkerPool
-
-				// dispatch the job to the worker job channel
-				jobChannel <- job
+				// this will block until a worker is idle or ctx is cancelled
+				select {
+				case jobChannel := <-d.WorkerPool:
+					jobChannel <- job
+				case <-ctx.Done():
+				}
 			}(job)
+
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
-func (d *Dispatcher) Run() {
+// Run starts the worker pool and the dispatch loop. When ctx is cancelled,
+// workers finish their current job and the dispatch loop exits.
+func (d *Dispatcher) Run(ctx context.Context) {
 	for i := 0; i < cap(d.WorkerPool); i++ {
 		worker := NewWorker(d.WorkerPool)
-		worker.Start()
+		d.workers = append(d.workers, worker)
+		d.workersWg.Add(1)
+		worker.Start(ctx, d.rawResults, &d.workersWg)
 	}
 
-	go d.dispatch()
+	d.wg.Add(3)
+	go func() {
+		defer d.wg.Done()
+		d.dispatch(ctx)
+	}()
+
+	// Close rawResults only once every worker has exited, so the forwarder
+	// below can drain it to completion with a plain range instead of racing
+	// a ctx.Done() select against results still sitting in the buffer -
+	// which would mark them incomplete in the spool despite having finished.
+	go func() {
+		defer d.wg.Done()
+		d.workersWg.Wait()
+		close(d.rawResults)
+	}()
+
+	// Workers publish to rawResults rather than Results directly so there is
+	// a single consumer to record spool completions; otherwise callers
+	// reading Results would race the spool bookkeeping for the same results.
+	go func() {
+		defer d.wg.Done()
+		for res := range d.rawResults {
+			if d.spool != nil {
+				// A job killed mid-flight by ctx cancellation never actually
+				// finished; leave its record StatusInFlight so it's replayed
+				// on the next run instead of being skipped as done-but-failed.
+				cancelled := ctx.Err() != nil && errors.Is(res.Err, ctx.Err())
+				if !cancelled {
+					status := StatusComplete
+					if res.Err != nil {
+						status = StatusFailed
+					}
+					d.spool.MarkComplete(res.JobID, status)
+				}
+			}
+			select {
+			case d.Results <- res:
+			case <-ctx.Done():
+			}
+		}
+	}()
+}
+
+// Wait blocks until every worker, the dispatch loop, and the results
+// forwarder have exited after ctx is cancelled. Useful for tests and callers
+// that reuse shared state (like JobQueue) across dispatcher instances and
+// need teardown to have actually finished first.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Stop signals every worker to quit.
+func (d *Dispatcher) Stop() {
+	for _, w := range d.workers {
+		w.Stop()
+	}
 }
 
 func main() {
-	d := NewDispatcher(5)
-	d.Run()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	JobQueue = make(chan Job)
+	JobQueue = make(chan Job, 100)
+	d := NewDispatcher(5)
+	d.Run(ctx)
 
+	for i := 0; i < 10; i++ {
+		JobQueue <- &PrintJob{JobId: fmt.Sprintf("job-%d", i), Payload: "hello"}
+	}
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// slowJob blocks until release is closed, so a test can control exactly when
+// in-flight jobs are still pending when a dispatcher gets killed.
+type slowJob struct {
+	PrintJob
+	release <-chan struct{}
+}
+
+func (j *slowJob) Do(ctx context.Context) (Result, error) {
+	select {
+	case <-j.release:
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+	return j.PrintJob.Do(ctx)
+}
+
+func TestSpoolResumesInterruptedJobs(t *testing.T) {
+	spoolPath := filepath.Join(t.TempDir(), "spool.bin")
+	release := make(chan struct{})
+
+	JobQueue = make(chan Job, 10)
+	ctx1, cancel1 := context.WithCancel(context.Background())
+
+	d1 := NewDispatcher(2)
+	if err := d1.WithSpool(spoolPath); err != nil {
+		t.Fatalf("WithSpool: %v", err)
+	}
+	d1.Run(ctx1)
+
+	const jobCount = 3
+	for i := 0; i < jobCount; i++ {
+		JobQueue <- &slowJob{PrintJob: PrintJob{JobId: fmt.Sprintf("job-%d", i), Payload: "x"}, release: release}
+	}
+
+	// give the dispatcher a moment to pick the jobs up and spool them as in-flight
+	time.Sleep(50 * time.Millisecond)
+	cancel1()
+	d1.Stop()
+	d1.Wait()
+	d1.spool.Close()
+
+	// restart: replay whatever never finished into a fresh dispatcher
+	JobQueue = make(chan Job, 10)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	d2 := NewDispatcher(2)
+	if err := d2.WithSpool(spoolPath); err != nil {
+		t.Fatalf("WithSpool (resume): %v", err)
+	}
+
+	seen := make(map[string]bool)
+	timeout := time.After(2 * time.Second)
+
+	d2.Run(ctx2)
+	close(release)
+
+	for i := 0; i < jobCount; i++ {
+		select {
+		case res := <-d2.Results:
+			seen[res.JobID] = true
+		case <-timeout:
+			t.Fatalf("timed out waiting for resumed jobs, saw %v", seen)
+		}
+	}
+
+	for i := 0; i < jobCount; i++ {
+		id := fmt.Sprintf("job-%d", i)
+		if !seen[id] {
+			t.Errorf("expected %s to complete exactly once after resume, never saw it", id)
+		}
+	}
+
+	cancel2()
+	d2.Stop()
+	d2.Wait()
+}